@@ -51,6 +51,19 @@ function randByte() {
   return Math.floor(Math.random() * 256);
 }
 
+// mulberry32：可设种子的伪随机数，用于“伪随机字节”模式，
+// 这样 Go 版本（go-demo/pattern）用同一个种子可以逐字节复现页面上的内容。
+function mulberry32(seed) {
+  let a = seed >>> 0;
+  return () => {
+    a = (a + 0x6d2b79f5) >>> 0;
+    let t = a;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return (t ^ (t >>> 14)) >>> 0;
+  };
+}
+
 function u64LE(bytes8) {
   let v = 0n;
   for (let i = 7; i >= 0; i--) {
@@ -84,8 +97,8 @@ function segmentNameAtIndex(i) {
   return "ret";
 }
 
-function mkPatternBytes(mode, customText) {
-  // 返回一个“无限序列”的生成器函数：idx -> byte
+function mkPatternBytes(mode, customText, seed) {
+  // 返回一个“无限序列”的生成器函数：idx -> byte（random 模式需按 idx 递增顺序调用）
   if (mode === "A") {
     return () => 0x41;
   }
@@ -94,7 +107,8 @@ function mkPatternBytes(mode, customText) {
     return (i) => seq[i % seq.length];
   }
   if (mode === "random") {
-    return () => randByte();
+    const next = mulberry32(seed ?? 1);
+    return () => next() >>> 24;
   }
   // custom
   const s = (customText ?? "").toString();
@@ -500,6 +514,8 @@ function init() {
   const pattern = el("pattern");
   const customRow = el("customRow");
   const customText = el("customText");
+  const seedRow = el("seedRow");
+  const seedInput = el("seed");
 
   const mitCanary = el("mitCanary");
   const mitNX = el("mitNX");
@@ -606,7 +622,8 @@ function init() {
     clearTimers();
     const mode = pattern.value;
     customRow.hidden = mode !== "custom";
-    const gen = mkPatternBytes(mode, customText.value);
+    seedRow.hidden = mode !== "random";
+    const gen = mkPatternBytes(mode, customText.value, Number(seedInput.value) >>> 0);
     const n = Number(writeLen.value);
     const bytes = new Uint8Array(n);
     for (let i = 0; i < n; i++) bytes[i] = gen(i);
@@ -696,6 +713,12 @@ function init() {
       rerender();
     }
   });
+  seedInput.addEventListener("input", () => {
+    if (pattern.value === "random") {
+      rebuildPlan();
+      rerender();
+    }
+  });
   writeLen.addEventListener("input", () => {
     rebuildPlan();
     rerender();
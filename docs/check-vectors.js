@@ -0,0 +1,60 @@
+// 用 Node.js 在 app.js 的概念模型上执行共享测试向量：
+//   node check-vectors.js
+// 向量文件与 Go 版本（go-demo/sim）共用：../go-demo/sim/vectors.json
+// app.js 依赖浏览器的 document，这里只提供一个最小占位，不会真正渲染页面。
+
+const fs = require("fs");
+const path = require("path");
+const vm = require("vm");
+
+const ctx = vm.createContext({ document: { addEventListener() {} } });
+vm.runInContext(fs.readFileSync(path.join(__dirname, "app.js"), "utf8"), ctx);
+
+const vectors = JSON.parse(
+  fs.readFileSync(path.join(__dirname, "..", "go-demo", "sim", "vectors.json"), "utf8")
+);
+
+ctx.vectors = vectors;
+const failures = vm.runInContext(
+  `
+  vectors.flatMap((v) => {
+    const hex = (s) => (s.match(/../g) ?? []).map((h) => parseInt(h, 16));
+    const fr = createFrame();
+    const canary = Uint8Array.from(hex(v.canary));
+    for (let i = 0; i < 8; i++) fr.mem[OFFSETS.canary + i] = canary[i];
+    fr.initial.canary = canary;
+
+    const st = {
+      mem: fr.mem,
+      initial: fr.initial,
+      written: new Array(FRAME_LEN).fill(false),
+      mitigations: v.mitigations,
+    };
+    hex(v.write).forEach((b, i) => {
+      const idx = OFFSETS.buf + i;
+      if (idx < st.mem.length) {
+        st.mem[idx] = b;
+        st.written[idx] = true;
+      }
+    });
+
+    const s = computeStatus(st);
+    const got = {
+      canaryChanged: s.canaryChanged,
+      rbpChanged: s.rbpChanged,
+      retChanged: s.retChanged,
+      level: s.verdict.level,
+      text: s.verdict.text,
+      writtenCount: s.writtenCount,
+    };
+    return Object.keys(v.expect)
+      .filter((k) => got[k] !== v.expect[k])
+      .map((k) => v.name + ": " + k + " = " + got[k] + ", want " + v.expect[k]);
+  })
+  `,
+  ctx
+);
+
+for (const f of failures) console.log("FAIL " + f);
+console.log(failures.length === 0 ? `ok: ${vectors.length} vectors` : `${failures.length} failure(s)`);
+process.exit(failures.length === 0 ? 0 : 1);
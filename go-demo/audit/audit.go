@@ -0,0 +1,480 @@
+// Package audit 找出 Go 代码里每一处 unsafe 用法，并对照 unsafe.Pointer 文档列出的
+// 合法转换模式逐一归类，标出无法证明合法的地方，便于跟踪一个仓库的“unsafe 债务”。
+//
+// 归类只看语法和类型，不做数据流分析：模式本身合法、但合法性取决于运行时边界的用法
+// （例如 main.go 里 uintptr 加下标的指针运算）标为 StatusUnproven，需要人工确认。
+package audit
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"runtime"
+	"strings"
+)
+
+// Status 是一处用法的结论。
+const (
+	StatusValid    = "valid"    // 符合文档模式，且不依赖运行时条件
+	StatusUnproven = "unproven" // 符合文档模式，但只有在结果仍位于原分配之内时才合法
+	StatusInvalid  = "invalid"  // 不符合任何文档模式
+)
+
+// 规则名。1–6 对应 unsafe.Pointer 文档里的六种模式，其余是 unsafe 包的函数。
+const (
+	Rule1Conversion = "1: *T1 -> Pointer -> *T2"
+	Rule2ToUintptr  = "2: Pointer -> uintptr"
+	Rule3Arithmetic = "3: Pointer -> uintptr +/- offset -> Pointer"
+	Rule4Syscall    = "4: uintptr argument to syscall"
+	Rule5Reflect    = "5: reflect Pointer/UnsafeAddr -> Pointer"
+	Rule6Header     = "6: reflect header Data <-> Pointer"
+	RuleAdd         = "unsafe.Add"
+	RuleSlice       = "unsafe.Slice"
+	RuleString      = "unsafe.String"
+	RuleData        = "unsafe.SliceData/StringData"
+	RuleCompileTime = "unsafe.Sizeof/Offsetof/Alignof"
+	RuleNone        = "none"
+)
+
+// Finding 是一处 unsafe 用法。
+type Finding struct {
+	Pos     string `json:"pos"` // file:line:col
+	Package string `json:"package"`
+	Rule    string `json:"rule"`
+	Status  string `json:"status"`
+	Expr    string `json:"expr"`
+	Note    string `json:"note,omitempty"`
+}
+
+// Check 审计一个已完成类型检查的包。info 至少需要 Types 与 Uses。
+// 模式 1 的大小比较使用 gc 编译器在当前 GOARCH 下的 Sizes。
+func Check(fset *token.FileSet, files []*ast.File, info *types.Info, pkgPath string) []Finding {
+	sizes := types.SizesFor("gc", runtime.GOARCH)
+	if sizes == nil {
+		sizes = types.SizesFor("gc", "amd64")
+	}
+	var out []Finding
+	for _, f := range files {
+		a := &auditor{fset: fset, info: info, pkg: pkgPath, done: make(map[ast.Node]bool), sizes: sizes}
+		a.file(f)
+		out = append(out, a.found...)
+	}
+	return out
+}
+
+type auditor struct {
+	fset  *token.FileSet
+	info  *types.Info
+	pkg   string
+	stack []ast.Node
+	done  map[ast.Node]bool // 已作为外层模式的一部分归类过的节点
+	found []Finding
+	sizes types.Sizes
+}
+
+func (a *auditor) file(f *ast.File) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil {
+			a.stack = a.stack[:len(a.stack)-1]
+			return true
+		}
+		if call, ok := n.(*ast.CallExpr); ok && !a.done[call] {
+			a.call(call)
+		}
+		a.stack = append(a.stack, n)
+		return true
+	})
+}
+
+// parent 返回当前节点（尚未入栈）的上层节点，跳过括号。
+func (a *auditor) parent() ast.Node {
+	for i := len(a.stack) - 1; i >= 0; i-- {
+		if _, ok := a.stack[i].(*ast.ParenExpr); !ok {
+			return a.stack[i]
+		}
+	}
+	return nil
+}
+
+func (a *auditor) report(n ast.Node, rule, status, note string) {
+	a.found = append(a.found, Finding{
+		Pos:     a.fset.Position(n.Pos()).String(),
+		Package: a.pkg,
+		Rule:    rule,
+		Status:  status,
+		Expr:    shorten(types.ExprString(n.(ast.Expr))),
+		Note:    note,
+	})
+}
+
+func (a *auditor) call(call *ast.CallExpr) {
+	if name, ok := a.unsafeFunc(call); ok {
+		a.builtin(call, name)
+		return
+	}
+	if !a.isConversion(call) {
+		return
+	}
+	to := a.info.TypeOf(call.Fun)
+	from := a.info.TypeOf(call.Args[0])
+	switch {
+	case isUnsafePointer(from) && isPointer(to):
+		a.fromPointer(call)
+	case isUnsafePointer(from) && isUintptr(to):
+		a.toUintptr(call)
+	case isUnsafePointer(to) && isPointer(from):
+		a.report(call, Rule1Conversion, StatusValid, "")
+	case isUnsafePointer(to) && isUintptr(from):
+		a.fromUintptr(call)
+	}
+}
+
+// fromPointer 处理 (*T)(p)，p 是 unsafe.Pointer。
+func (a *auditor) fromPointer(call *ast.CallExpr) {
+	if inner, ok := ast.Unparen(call.Args[0]).(*ast.CallExpr); ok {
+		if _, ok := a.unsafeFunc(inner); ok {
+			return // 由 unsafe.Add 等函数自己归类
+		}
+		if a.isConversion(inner) && isUnsafePointer(a.info.TypeOf(inner.Fun)) {
+			switch src := a.info.TypeOf(inner.Args[0]); {
+			case isPointer(src):
+				a.done[inner] = true
+				a.rule1(call, src)
+				return
+			case isUintptr(src):
+				// (*T)(unsafe.Pointer(uintptr(p) + off))：作为模式 3 整体归类。
+				a.done[inner] = true
+				a.fromUintptrAs(inner, call)
+				return
+			}
+		}
+	}
+	a.rule1(call, nil)
+}
+
+// rule1 按模式 1 归类 (*T2)(unsafe.Pointer(x))：x 的类型 *T1 已知时（from 非 nil）比较两者的大小，
+// T2 更大时为 invalid；T1 未知或大小取决于类型参数时为 unproven。
+// 布局是否等价无法从类型上判断，大小满足时按 valid 处理。
+func (a *auditor) rule1(call *ast.CallExpr, from types.Type) {
+	t2 := a.info.TypeOf(call.Fun).Underlying().(*types.Pointer).Elem()
+	if hasTypeParam(t2) {
+		a.report(call, Rule1Conversion, StatusUnproven, fmt.Sprintf("size of %s depends on the type argument", a.typeString(t2)))
+		return
+	}
+	s2 := a.sizes.Sizeof(t2)
+	if from == nil {
+		a.report(call, Rule1Conversion, StatusUnproven,
+			fmt.Sprintf("source type unknown: valid only if the pointer addresses at least sizeof(%s) = %d bytes of an equivalent layout", a.typeString(t2), s2))
+		return
+	}
+	t1 := from.Underlying().(*types.Pointer).Elem()
+	if hasTypeParam(t1) {
+		a.report(call, Rule1Conversion, StatusUnproven, fmt.Sprintf("size of %s depends on the type argument", a.typeString(t1)))
+		return
+	}
+	if s1 := a.sizes.Sizeof(t1); s2 > s1 {
+		a.report(call, Rule1Conversion, StatusInvalid,
+			fmt.Sprintf("sizeof(%s) = %d is larger than sizeof(%s) = %d", a.typeString(t2), s2, a.typeString(t1), s1))
+		return
+	}
+	a.report(call, Rule1Conversion, StatusValid, "")
+}
+
+// toUintptr 处理 uintptr(p)，p 是 unsafe.Pointer。
+func (a *auditor) toUintptr(call *ast.CallExpr) {
+	a.consumePointerConv(call.Args[0])
+	if p, ok := a.parent().(*ast.CallExpr); ok && a.isSyscall(p) {
+		a.report(call, Rule4Syscall, StatusValid, "")
+		return
+	}
+	a.report(call, Rule2ToUintptr, StatusValid, "the uintptr is only a number: the object may move or be freed")
+}
+
+// fromUintptr 处理 unsafe.Pointer(u)，u 是 uintptr。
+func (a *auditor) fromUintptr(call *ast.CallExpr) {
+	a.fromUintptrAs(call, call)
+}
+
+// fromUintptrAs 归类 conv = unsafe.Pointer(u)，并把结果报告在 at（conv 本身或包住它的 (*T)(...)）上。
+func (a *auditor) fromUintptrAs(conv *ast.CallExpr, at ast.Expr) {
+	arg := ast.Unparen(conv.Args[0])
+
+	if m, ok := arg.(*ast.CallExpr); ok {
+		if sel, ok := ast.Unparen(m.Fun).(*ast.SelectorExpr); ok && (sel.Sel.Name == "Pointer" || sel.Sel.Name == "UnsafeAddr") && a.isReflectValue(sel.X) {
+			a.report(at, Rule5Reflect, StatusValid, "")
+			return
+		}
+	}
+	if sel, ok := arg.(*ast.SelectorExpr); ok && sel.Sel.Name == "Data" && a.isReflectHeader(sel.X) {
+		a.report(at, Rule6Header, StatusValid, "reflect.SliceHeader/StringHeader are deprecated: prefer unsafe.Slice/unsafe.String")
+		return
+	}
+
+	// 模式 3：表达式里必须有 uintptr(p)，且转换和运算在同一个表达式里完成。
+	ts, masks := a.terms(arg)
+	var bases, offsets []ast.Expr
+	for _, t := range ts {
+		if c, ok := t.(*ast.CallExpr); ok && a.isConversion(c) && isUintptr(a.info.TypeOf(c.Fun)) && isUnsafePointer(a.info.TypeOf(c.Args[0])) {
+			a.done[c] = true
+			a.consumePointerConv(c.Args[0])
+			bases = append(bases, t)
+			continue
+		}
+		offsets = append(offsets, t)
+	}
+	switch {
+	case len(bases) == 0:
+		a.report(at, RuleNone, StatusInvalid, "uintptr converted back to Pointer outside the expression that produced it: the GC may have moved or freed the object")
+	case len(bases) == 1 && len(masks) > 0 && a.allConst(masks) && (len(offsets) == 0 || a.allOffsetof(offsets)):
+		a.report(at, Rule3Arithmetic, StatusValid, "address rounded with a constant mask")
+	case len(bases) == 1 && len(masks) == 0 && a.allOffsetof(offsets):
+		a.report(at, Rule3Arithmetic, StatusValid, "offset is a field offset of the same object")
+	default:
+		a.report(at, Rule3Arithmetic, StatusUnproven, "valid only if the result stays inside the original allocation")
+	}
+}
+
+// consumePointerConv 把 uintptr(unsafe.Pointer(&x)) 里的内层转换记为已归类，避免重复报告。
+func (a *auditor) consumePointerConv(e ast.Expr) {
+	if c, ok := ast.Unparen(e).(*ast.CallExpr); ok && a.isConversion(c) && isUnsafePointer(a.info.TypeOf(c.Fun)) && isPointer(a.info.TypeOf(c.Args[0])) {
+		a.done[c] = true
+	}
+}
+
+func (a *auditor) builtin(call *ast.CallExpr, name string) {
+	switch name {
+	case "Sizeof", "Offsetof", "Alignof":
+		a.report(call, RuleCompileTime, StatusValid, "")
+	case "SliceData", "StringData":
+		a.report(call, RuleData, StatusValid, "")
+	case "Add":
+		a.consumePointerConv(call.Args[0])
+		if ts, masks := a.terms(call.Args[1]); len(masks) == 0 && a.allOffsetof(ts) {
+			a.report(call, RuleAdd, StatusValid, "offset is a field offset of the same object")
+			return
+		}
+		a.report(call, RuleAdd, StatusUnproven, "valid only if the result stays inside the original allocation")
+	case "Slice":
+		if a.lenFits(call.Args[0], call.Args[1]) {
+			a.report(call, RuleSlice, StatusValid, "length fits the array or slice the pointer came from")
+			return
+		}
+		a.report(call, RuleSlice, StatusUnproven, "valid only if the pointer addresses at least len elements")
+	case "String":
+		if a.lenFits(call.Args[0], call.Args[1]) {
+			a.report(call, RuleString, StatusValid, "length fits the slice or string the pointer came from")
+			return
+		}
+		a.report(call, RuleString, StatusUnproven, "valid only if the pointer addresses at least len bytes that are never modified")
+	}
+}
+
+// lenFits 识别两种能证明长度合法的写法：
+// &arr[0] 配合不超过数组长度的常量，以及 unsafe.SliceData(s)/unsafe.StringData(s)/&s[0] 配合 len(s)/cap(s)。
+func (a *auditor) lenFits(ptr, n ast.Expr) bool {
+	var src ast.Expr
+	switch p := ast.Unparen(ptr).(type) {
+	case *ast.UnaryExpr:
+		ix, ok := ast.Unparen(p.X).(*ast.IndexExpr)
+		if p.Op != token.AND || !ok || !isZero(a.info, ix.Index) {
+			return false
+		}
+		src = ix.X
+		t := a.info.TypeOf(src).Underlying()
+		if pt, ok := t.(*types.Pointer); ok {
+			t = pt.Elem().Underlying()
+		}
+		if arr, ok := t.(*types.Array); ok {
+			if tv, ok := a.info.Types[n]; ok && tv.Value != nil {
+				v, exact := constant.Int64Val(constant.ToInt(tv.Value))
+				return exact && v >= 0 && v <= arr.Len()
+			}
+		}
+	case *ast.CallExpr:
+		if name, ok := a.unsafeFunc(p); ok && (name == "SliceData" || name == "StringData") {
+			src = p.Args[0]
+		}
+	}
+	if src == nil {
+		return false
+	}
+	c, ok := ast.Unparen(n).(*ast.CallExpr)
+	if !ok || len(c.Args) != 1 {
+		return false
+	}
+	b, ok := a.info.Uses[calleeIdent(c.Fun)].(*types.Builtin)
+	if !ok || (b.Name() != "len" && b.Name() != "cap") {
+		return false
+	}
+	if b.Name() == "cap" && !isSlice(a.info.TypeOf(src)) {
+		return false
+	}
+	return types.ExprString(c.Args[0]) == types.ExprString(src)
+}
+
+// allOffsetof 报告 offsets 是否非空且全部是 unsafe.Offsetof 调用。
+func (a *auditor) allOffsetof(offsets []ast.Expr) bool {
+	for _, o := range offsets {
+		c, ok := o.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if name, ok := a.unsafeFunc(c); !ok || name != "Offsetof" {
+			return false
+		}
+	}
+	return len(offsets) > 0
+}
+
+// allConst 报告 es 是否全部是常量表达式。
+func (a *auditor) allConst(es []ast.Expr) bool {
+	for _, e := range es {
+		if tv, ok := a.info.Types[e]; !ok || tv.Value == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// terms 把 a + b - c 展开成各项。x &^ m 和 x & m（另一侧是常量）是对 x 的取整（模式 3 允许的运算），
+// 继续展开 x，掩码 m 放进 masks。
+func (a *auditor) terms(e ast.Expr) (ts, masks []ast.Expr) {
+	e = ast.Unparen(e)
+	b, ok := e.(*ast.BinaryExpr)
+	if !ok {
+		return []ast.Expr{e}, nil
+	}
+	x, y := b.X, b.Y
+	switch b.Op {
+	case token.ADD, token.SUB:
+	case token.AND:
+		if a.allConst([]ast.Expr{x}) {
+			x, y = y, x
+		}
+		if !a.allConst([]ast.Expr{y}) {
+			return []ast.Expr{e}, nil
+		}
+		fallthrough
+	case token.AND_NOT:
+		ts, masks = a.terms(x)
+		return ts, append(masks, ast.Unparen(y))
+	default:
+		return []ast.Expr{e}, nil
+	}
+	xt, xm := a.terms(x)
+	yt, ym := a.terms(y)
+	return append(xt, yt...), append(xm, ym...)
+}
+
+func (a *auditor) unsafeFunc(call *ast.CallExpr) (string, bool) {
+	b, ok := a.info.Uses[calleeIdent(call.Fun)].(*types.Builtin)
+	if !ok || b.Pkg() == nil || b.Pkg().Path() != "unsafe" {
+		return "", false
+	}
+	return b.Name(), true
+}
+
+func (a *auditor) isConversion(call *ast.CallExpr) bool {
+	tv, ok := a.info.Types[call.Fun]
+	return ok && tv.IsType() && len(call.Args) == 1
+}
+
+// isSyscall 报告 call 是否调用 syscall 或 golang.org/x/sys 里的 Syscall*/RawSyscall* 函数。
+func (a *auditor) isSyscall(call *ast.CallExpr) bool {
+	fn, ok := a.info.Uses[calleeIdent(call.Fun)].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+	path := fn.Pkg().Path()
+	if path != "syscall" && !strings.HasPrefix(path, "golang.org/x/sys/") {
+		return false
+	}
+	return strings.HasPrefix(fn.Name(), "Syscall") || strings.HasPrefix(fn.Name(), "RawSyscall")
+}
+
+func (a *auditor) isReflectValue(e ast.Expr) bool {
+	return isNamed(a.info.TypeOf(e), "reflect", "Value")
+}
+
+func (a *auditor) isReflectHeader(e ast.Expr) bool {
+	t := a.info.TypeOf(e)
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	return isNamed(t, "reflect", "SliceHeader") || isNamed(t, "reflect", "StringHeader")
+}
+
+func isNamed(t types.Type, pkg, name string) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == pkg && n.Obj().Name() == name
+}
+
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	}
+	return nil
+}
+
+// typeString 以包名（而不是完整导入路径）限定类型名。
+func (a *auditor) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string { return p.Name() })
+}
+
+// hasTypeParam 报告 t 的大小是否取决于类型参数。
+func hasTypeParam(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Array:
+		return hasTypeParam(t.Elem())
+	case *types.Named:
+		return hasTypeParam(t.Underlying())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if hasTypeParam(t.Field(i).Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isUnsafePointer(t types.Type) bool {
+	b, ok := t.(*types.Basic)
+	return ok && b.Kind() == types.UnsafePointer
+}
+
+func isUintptr(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Uintptr
+}
+
+func isPointer(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Pointer)
+	return ok
+}
+
+func isSlice(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Slice)
+	return ok
+}
+
+func isZero(info *types.Info, e ast.Expr) bool {
+	tv, ok := info.Types[e]
+	return ok && tv.Value != nil && constant.Sign(tv.Value) == 0
+}
+
+// shorten 把过长的表达式截断，让表格保持可读。
+func shorten(s string) string {
+	const max = 72
+	if r := []rune(s); len(r) > max {
+		return string(r[:max-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var wantRE = regexp.MustCompile(`// want "(\w+)"`)
+
+// checkFixture 审计 testdata/<name>/<name>.go，对 rules 中的规则逐行比较结论与 want 注释：
+// 每个带 want 的行必须恰好有一个这些规则的结论，且状态相同；没有 want 的行不能有。
+// keep 非 nil 时只比较它接受的结论。
+func checkFixture(t *testing.T, name string, keep func(Finding) bool, rules ...string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	path := filepath.Join("testdata", name, name+".go")
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}, Uses: map[*ast.Ident]types.Object{}}
+	conf := types.Config{Importer: importer.Default()}
+	if _, err := conf.Check(name, fset, []*ast.File{f}, info); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]string{}
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			if m := wantRE.FindStringSubmatch(c.Text); m != nil {
+				want[fset.Position(c.Pos()).Line] = m[1]
+			}
+		}
+	}
+
+	got := map[int]string{}
+	for _, fd := range Check(fset, []*ast.File{f}, info, name) {
+		if !slices.Contains(rules, fd.Rule) || (keep != nil && !keep(fd)) {
+			continue
+		}
+		parts := strings.Split(fd.Pos, ":") // file:line:col
+		line, err := strconv.Atoi(parts[len(parts)-2])
+		if err != nil {
+			t.Fatalf("bad position %q: %v", fd.Pos, err)
+		}
+		if prev, ok := got[line]; ok {
+			t.Errorf("%s: %s: second finding on the line (first: %s)", fd.Pos, fd.Expr, prev)
+		}
+		got[line] = fd.Status
+		if fd.Status != want[line] {
+			t.Errorf("%s: %s: got %s [%s], want %q (%s)", fd.Pos, fd.Expr, fd.Status, fd.Rule, want[line], fd.Note)
+		}
+	}
+	for line, st := range want {
+		if _, ok := got[line]; !ok {
+			t.Errorf("%s:%d: no finding for %q, want %s", path, line, rules, st)
+		}
+	}
+}
+
+// TestRule1 比较模式 1 的结论。只比较 (*T2)(...)，不比较单独的 unsafe.Pointer(&x)。
+func TestRule1(t *testing.T) {
+	checkFixture(t, "rule1", func(fd Finding) bool { return strings.HasPrefix(fd.Expr, "(*") }, Rule1Conversion)
+}
+
+// TestRule3 比较模式 3 的结论；转换和运算不在同一个表达式里时归为 RuleNone。
+func TestRule3(t *testing.T) {
+	checkFixture(t, "rule3", nil, Rule3Arithmetic, RuleNone)
+}
+
+// TestReflect 比较模式 5、6 的结论。
+func TestReflect(t *testing.T) {
+	checkFixture(t, "reflect", nil, Rule5Reflect, Rule6Header, RuleNone)
+}
+
+// TestBuiltins 比较 unsafe.Add、unsafe.Slice、unsafe.String 的结论。
+func TestBuiltins(t *testing.T) {
+	checkFixture(t, "builtins", nil, RuleAdd, RuleSlice, RuleString)
+}
@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+)
+
+// Report 是一次审计的结果。
+type Report struct {
+	Dir      string         `json:"dir"`
+	Patterns []string       `json:"patterns"`
+	Findings []Finding      `json:"findings"`
+	Counts   map[string]int `json:"counts"` // 按 Status 计数
+	Errors   []string       `json:"errors,omitempty"`
+}
+
+// listedPackage 是 go list -json 输出中用到的字段。
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	CgoFiles   []string
+}
+
+// Module 用 go list 列出 dir 下匹配 patterns 的包（默认 ./...），逐个类型检查并审计。
+// 依赖从源码导入；类型检查错误不会中断审计，只记录在 Report.Errors 里。
+func Module(dir string, patterns ...string) (*Report, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	cmd := exec.Command("go", append([]string{"list", "-e", "-json=ImportPath,Dir,GoFiles,CgoFiles"}, patterns...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("audit: go list: %v\n%s", err, stderr.Bytes())
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{Dir: dir, Patterns: patterns, Findings: []Finding{}, Counts: map[string]int{}}
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "source", nil)
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var lp listedPackage
+		if err := dec.Decode(&lp); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("audit: decode go list output: %w", err)
+		}
+		r.Findings = append(r.Findings, r.checkPackage(fset, imp, lp)...)
+	}
+	for i := range r.Findings {
+		if rel, err := filepath.Rel(abs, r.Findings[i].Pos); err == nil && !filepath.IsAbs(rel) {
+			r.Findings[i].Pos = rel
+		}
+		r.Counts[r.Findings[i].Status]++
+	}
+	return r, nil
+}
+
+func (r *Report) checkPackage(fset *token.FileSet, imp types.Importer, lp listedPackage) []Finding {
+	var files []*ast.File
+	for _, name := range append(lp.GoFiles, lp.CgoFiles...) {
+		f, err := parser.ParseFile(fset, filepath.Join(lp.Dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			r.Errors = append(r.Errors, err.Error())
+			continue
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}, Uses: map[*ast.Ident]types.Object{}}
+	var typeErrs int
+	conf := types.Config{Importer: imp, FakeImportC: true, Error: func(error) { typeErrs++ }}
+	conf.Check(lp.ImportPath, fset, files, info)
+	if typeErrs > 0 {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s: %d type-check errors; findings may be incomplete", lp.ImportPath, typeErrs))
+	}
+	return Check(fset, files, info, lp.ImportPath)
+}
+
+// WriteTable 以对齐的文本表格输出，最后一行是按结论的汇总。
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "POSITION\tRULE\tSTATUS\tEXPRESSION\tNOTE")
+	for _, f := range r.Findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", f.Pos, f.Rule, f.Status, f.Expr, f.Note)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintf(w, "warning: %s\n", e)
+	}
+	_, err := fmt.Fprintf(w, "\n%s\n", r.Summary())
+	return err
+}
+
+// Summary 返回形如 "12 unsafe uses: 8 valid, 3 unproven, 1 invalid" 的汇总。
+func (r *Report) Summary() string {
+	s := fmt.Sprintf("%d unsafe uses", len(r.Findings))
+	statuses := make([]string, 0, len(r.Counts))
+	for st := range r.Counts {
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statusRank(statuses[i]) < statusRank(statuses[j]) })
+	for i, st := range statuses {
+		sep := ", "
+		if i == 0 {
+			sep = ": "
+		}
+		s += fmt.Sprintf("%s%d %s", sep, r.Counts[st], st)
+	}
+	return s
+}
+
+func statusRank(s string) int {
+	switch s {
+	case StatusValid:
+		return 0
+	case StatusUnproven:
+		return 1
+	}
+	return 2
+}
+
+// WriteJSON 以缩进的 JSON 输出。
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,39 @@
+// Package builtins 是 unsafe.Add、unsafe.Slice、unsafe.String 的审计用例：
+// 每个调用后面的 want 注释是期望的结论。
+package builtins
+
+import "unsafe"
+
+type rec struct {
+	id  uint32
+	buf [8]byte
+}
+
+func add(r *rec, p unsafe.Pointer, n int) {
+	_ = unsafe.Add(unsafe.Pointer(r), unsafe.Offsetof(r.buf)) // want "valid"
+	_ = unsafe.Add(p, n)                                      // want "unproven"
+	_ = unsafe.Add(p, 4)                                      // want "unproven"
+	_ = unsafe.Add(p, unsafe.Offsetof(r.buf)&^3)              // want "unproven"
+}
+
+func slice(arr [4]byte, pa *[4]byte, s []byte, p *byte, n int) {
+	_ = unsafe.Slice(&arr[0], 4)                    // want "valid"
+	_ = unsafe.Slice(&arr[0], 2)                    // want "valid"
+	_ = unsafe.Slice(&arr[0], 5)                    // want "unproven"
+	_ = unsafe.Slice(&pa[0], len(pa))               // want "valid"
+	_ = unsafe.Slice(&pa[0], 4)                     // want "valid"
+	_ = unsafe.Slice(unsafe.SliceData(s), len(s))   // want "valid"
+	_ = unsafe.Slice(unsafe.SliceData(s), cap(s))   // want "valid"
+	_ = unsafe.Slice(&s[0], len(s))                 // want "valid"
+	_ = unsafe.Slice(unsafe.SliceData(s), len(s)+1) // want "unproven"
+	_ = unsafe.Slice(p, n)                          // want "unproven"
+	_ = unsafe.Slice(&arr[1], 3)                    // want "unproven"
+}
+
+func str(s string, b []byte, n int) {
+	_ = unsafe.String(unsafe.StringData(s), len(s)) // want "valid"
+	_ = unsafe.String(&b[0], len(b))                // want "valid"
+	_ = unsafe.String(unsafe.SliceData(b), cap(b))  // want "valid"
+	_ = unsafe.String(&b[0], n)                     // want "unproven"
+	_ = unsafe.String(unsafe.StringData(s), len(b)) // want "unproven"
+}
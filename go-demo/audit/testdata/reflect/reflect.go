@@ -0,0 +1,29 @@
+// Package reflect 是模式 5、6（reflect 返回的 uintptr）的审计用例：
+// 每个转换后面的 want 注释是期望的结论。
+package reflect
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+func rule5(v reflect.Value) {
+	_ = unsafe.Pointer(v.Pointer())                   // want "valid"
+	_ = (*int)(unsafe.Pointer(v.UnsafeAddr()))        // want "valid"
+	_ = unsafe.Pointer(reflect.ValueOf(&v).Pointer()) // want "valid"
+
+	u := v.Pointer()
+	_ = unsafe.Pointer(u) // want "invalid"
+}
+
+func rule6(s []byte, str string) {
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	_ = unsafe.Pointer(h.Data) // want "valid"
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&str))
+	_ = (*byte)(unsafe.Pointer(sh.Data)) // want "valid"
+
+	var copied reflect.SliceHeader
+	copied.Data = h.Data
+	data := copied.Data
+	_ = unsafe.Pointer(data) // want "invalid"
+}
@@ -0,0 +1,38 @@
+// Package rule1 是模式 1（*T1 -> Pointer -> *T2）的审计用例：
+// 每个转换后面的 want 注释是期望的结论。
+package rule1
+
+import "unsafe"
+
+type pair struct {
+	a, b uint32
+}
+
+func valid() {
+	var x uint64
+	_ = (*[8]byte)(unsafe.Pointer(&x)) // want "valid"
+	_ = (*byte)(unsafe.Pointer(&x))    // want "valid"
+	var p pair
+	_ = (*uint32)(unsafe.Pointer(&p)) // want "valid"
+}
+
+func invalid() {
+	var b byte
+	_ = (*uint64)(unsafe.Pointer(&b)) // want "invalid"
+	var p pair
+	_ = (*[3]uint32)(unsafe.Pointer(&p)) // want "invalid"
+}
+
+func unproven(p unsafe.Pointer) {
+	_ = (*int)(p)                   // want "unproven"
+	_ = (*int)(pointer())           // want "unproven"
+	_ = (*[4]byte)(generic[int](p)) // want "unproven"
+}
+
+func pointer() unsafe.Pointer { return nil }
+
+func generic[T any](p unsafe.Pointer) unsafe.Pointer {
+	var t T
+	_ = (*byte)(unsafe.Pointer(&t)) // want "unproven"
+	return p
+}
@@ -0,0 +1,41 @@
+// Package rule3 是模式 3（Pointer -> uintptr 运算 -> Pointer）的审计用例：
+// 每个转换后面的 want 注释是期望的结论。
+package rule3
+
+import "unsafe"
+
+type rec struct {
+	id  uint32
+	tag uint16
+	buf [10]byte
+}
+
+// addr 是底层类型为 uintptr 的具名类型。
+type addr uintptr
+
+const align = ^uintptr(15)
+
+func offsets(r *rec, n uintptr) {
+	_ = unsafe.Pointer(uintptr(unsafe.Pointer(r)) + unsafe.Offsetof(r.tag))            // want "valid"
+	_ = (*uint16)(unsafe.Pointer(uintptr(unsafe.Pointer(r)) + unsafe.Offsetof(r.tag))) // want "valid"
+	_ = unsafe.Pointer(uintptr(unsafe.Pointer(r)) + n)                                 // want "unproven"
+	_ = unsafe.Pointer(uintptr(unsafe.Pointer(r)) + 4 - 2)                             // want "unproven"
+	_ = unsafe.Pointer(addr(unsafe.Pointer(r)) + 8)                                    // want "unproven"
+}
+
+func rounding(p unsafe.Pointer, m uintptr) {
+	_ = unsafe.Pointer(uintptr(p) &^ 15)                 // want "valid"
+	_ = unsafe.Pointer(uintptr(p) & align)               // want "valid"
+	_ = unsafe.Pointer(align & uintptr(p))               // want "valid"
+	_ = unsafe.Pointer((uintptr(p) + 15) &^ 15)          // want "unproven"
+	_ = unsafe.Pointer(uintptr(p) &^ m)                  // want "unproven"
+	_ = unsafe.Pointer(uintptr(p) & m)                   // want "invalid"
+	_ = unsafe.Pointer(addr(p) &^ 7)                     // want "valid"
+	_ = unsafe.Pointer(uintptr(p) + uintptr(p)&^15 - 16) // want "unproven"
+}
+
+func split(p unsafe.Pointer) {
+	u := uintptr(p)
+	_ = unsafe.Pointer(u + 8) // want "invalid"
+	_ = unsafe.Pointer(u)     // want "invalid"
+}
@@ -0,0 +1,48 @@
+// Package checked 提供溢出时返回错误而不是回绕的整数运算。
+//
+// 协议代码常用 uint8 / uint16 计算长度（条目数 × 条目大小 + 头部），
+// 结果回绕成一个很小的值后按它分配缓冲区，再按真实长度写入，就得到了越界写。
+// 在计算长度的地方改用 AddChecked / MulChecked，回绕就变成了一个可以拒绝的错误。
+package checked
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOverflow 表示运算结果超出了类型的范围。AddChecked 与 MulChecked 返回的错误满足 errors.Is(err, ErrOverflow)。
+var ErrOverflow = errors.New("checked: integer overflow")
+
+// Integer 是所有整数类型。
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// AddChecked 返回 a + b；结果超出 T 的范围时返回 0 和错误。
+func AddChecked[T Integer](a, b T) (T, error) {
+	s := a + b
+	if (b > 0 && s < a) || (b < 0 && s > a) {
+		return 0, overflow("+", a, b)
+	}
+	return s, nil
+}
+
+// MulChecked 返回 a * b；结果超出 T 的范围时返回 0 和错误。
+func MulChecked[T Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	p := a * b
+	// 有符号类型里 -1 * MinInt 回绕成 MinInt，而 MinInt / -1 也是 MinInt，除法验证不出来，单独判断。
+	minusOne := a < 0 && a+1 == 0
+	isMin := b < 0 && -b == b
+	if p/a != b || (minusOne && isMin) {
+		return 0, overflow("*", a, b)
+	}
+	return p, nil
+}
+
+func overflow[T Integer](op string, a, b T) error {
+	return fmt.Errorf("%w: %d %s %d overflows %T", ErrOverflow, a, op, b, a)
+}
@@ -0,0 +1,127 @@
+package checked
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type testCase[T Integer] struct {
+	a, b T
+	want T
+	ok   bool
+}
+
+func checkAdd[T Integer](t *testing.T, cases []testCase[T]) {
+	t.Helper()
+	for _, c := range cases {
+		got, err := AddChecked(c.a, c.b)
+		if c.ok && (err != nil || got != c.want) {
+			t.Errorf("AddChecked(%d, %d) = %d, %v; want %d, nil", c.a, c.b, got, err, c.want)
+		}
+		if !c.ok && (!errors.Is(err, ErrOverflow) || got != 0) {
+			t.Errorf("AddChecked(%d, %d) = %d, %v; want 0, ErrOverflow", c.a, c.b, got, err)
+		}
+	}
+}
+
+func checkMul[T Integer](t *testing.T, cases []testCase[T]) {
+	t.Helper()
+	for _, c := range cases {
+		got, err := MulChecked(c.a, c.b)
+		if c.ok && (err != nil || got != c.want) {
+			t.Errorf("MulChecked(%d, %d) = %d, %v; want %d, nil", c.a, c.b, got, err, c.want)
+		}
+		if !c.ok && (!errors.Is(err, ErrOverflow) || got != 0) {
+			t.Errorf("MulChecked(%d, %d) = %d, %v; want 0, ErrOverflow", c.a, c.b, got, err)
+		}
+	}
+}
+
+func TestAddChecked(t *testing.T) {
+	checkAdd(t, []testCase[int8]{
+		{1, 2, 3, true},
+		{math.MaxInt8, 0, math.MaxInt8, true},
+		{math.MaxInt8, 1, 0, false},
+		{math.MinInt8, -1, 0, false},
+		{math.MinInt8, math.MaxInt8, -1, true},
+		{-1, math.MinInt8, 0, false},
+	})
+	checkAdd(t, []testCase[int64]{
+		{math.MaxInt64, 1, 0, false},
+		{math.MinInt64, -1, 0, false},
+		{math.MinInt64, 1, math.MinInt64 + 1, true},
+	})
+	checkAdd(t, []testCase[uint8]{
+		{math.MaxUint8, 0, math.MaxUint8, true},
+		{math.MaxUint8, 1, 0, false},
+		{200, 56, 0, false},
+		{200, 55, 255, true},
+	})
+	checkAdd(t, []testCase[uint64]{
+		{math.MaxUint64, 1, 0, false},
+		{math.MaxUint64 - 1, 1, math.MaxUint64, true},
+	})
+}
+
+func TestMulChecked(t *testing.T) {
+	checkMul(t, []testCase[int8]{
+		{0, math.MinInt8, 0, true},
+		{math.MinInt8, 0, 0, true},
+		{1, math.MinInt8, math.MinInt8, true},
+		{math.MinInt8, 1, math.MinInt8, true},
+		{-1, math.MaxInt8, -math.MaxInt8, true},
+		{math.MaxInt8, -1, -math.MaxInt8, true},
+		{math.MinInt8, -1, 0, false},
+		{-1, math.MinInt8, 0, false},
+		{-16, 8, math.MinInt8, true},
+		{16, 8, 0, false},
+	})
+	checkMul(t, []testCase[int64]{
+		{math.MinInt64, -1, 0, false},
+		{-1, math.MinInt64, 0, false},
+		{math.MinInt64, 1, math.MinInt64, true},
+		{math.MaxInt64, 2, 0, false},
+	})
+	checkMul(t, []testCase[uint8]{
+		{0, math.MaxUint8, 0, true},
+		{1, math.MaxUint8, math.MaxUint8, true},
+		{16, 16, 0, false},
+		{13, 20, 0, false}, // scenario intwrap 的长度
+		{15, 17, 255, true},
+	})
+	checkMul(t, []testCase[uint64]{
+		{1 << 32, 1 << 32, 0, false},
+		{1 << 32, 1<<32 - 1, 1<<64 - 1<<32, true},
+	})
+}
+
+// TestExhaustive8 用 int 运算对照 int8、uint8 的全部组合。
+func TestExhaustive8(t *testing.T) {
+	for a := math.MinInt8; a <= math.MaxInt8; a++ {
+		for b := math.MinInt8; b <= math.MaxInt8; b++ {
+			check8(t, "AddChecked", int8(a), int8(b), a+b, AddChecked[int8], math.MinInt8, math.MaxInt8)
+			check8(t, "MulChecked", int8(a), int8(b), a*b, MulChecked[int8], math.MinInt8, math.MaxInt8)
+		}
+	}
+	for a := 0; a <= math.MaxUint8; a++ {
+		for b := 0; b <= math.MaxUint8; b++ {
+			check8(t, "AddChecked", uint8(a), uint8(b), a+b, AddChecked[uint8], 0, math.MaxUint8)
+			check8(t, "MulChecked", uint8(a), uint8(b), a*b, MulChecked[uint8], 0, math.MaxUint8)
+		}
+	}
+}
+
+func check8[T int8 | uint8](t *testing.T, op string, a, b T, wide int, fn func(T, T) (T, error), lo, hi int) {
+	t.Helper()
+	got, err := fn(a, b)
+	if wide < lo || wide > hi {
+		if !errors.Is(err, ErrOverflow) {
+			t.Fatalf("%s[%T](%d, %d) = %d, %v; want ErrOverflow", op, a, a, b, got, err)
+		}
+		return
+	}
+	if err != nil || int(got) != wide {
+		t.Fatalf("%s[%T](%d, %d) = %d, %v; want %d", op, a, a, b, got, err, wide)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"shijian/frame"
+	"shijian/harness"
+)
+
+// traceEnv 让子进程在每次 unsafe 写入前把偏移打印到 stderr，父进程据此判断“在哪个字节被拦下”。
+const (
+	traceEnv    = "SHIJIAN_TRACE_WRITES"
+	tracePrefix = "shijian-trace: off="
+)
+
+// enableWriteTrace 在设置了 traceEnv 时打开 frame.WriteHook。
+func enableWriteTrace() {
+	if os.Getenv(traceEnv) == "" {
+		return
+	}
+	frame.WriteHook = func(off int64) {
+		fmt.Fprintf(os.Stderr, "%s%d\n", tracePrefix, off)
+	}
+}
+
+// checkptrResult 是一个场景在 checkptr 构建下的运行结果。
+type checkptrResult struct {
+	name    string
+	caught  bool
+	lastOff int64 // 被拦下前最后一次（即正在进行的）写入偏移；-1 表示没有写入
+	message string
+	exit    int
+}
+
+// runCheckptr 用 -gcflags=all=-d=checkptr 重新构建本程序，并在子进程中逐个运行场景。
+func runCheckptr(names []string) error {
+	dir, err := moduleDir()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp("", "shijian-checkptr")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	bin := filepath.Join(tmp, "shijian")
+	fmt.Printf("building with -gcflags=all=-d=checkptr ...\n\n")
+	build := exec.Command("go", "build", "-gcflags=all=-d=checkptr", "-o", bin, ".")
+	build.Dir, build.Stdout, build.Stderr = dir, os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("checkptr build: %w", err)
+	}
+
+	var results []checkptrResult
+	for _, name := range names {
+		results = append(results, runCheckptrChild(bin, name))
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCENARIO\tCAUGHT\tAT BYTE\tEXIT\tRUNTIME MESSAGE")
+	for _, r := range results {
+		caught, at := "no", "-"
+		if r.caught {
+			caught = "yes"
+			if r.lastOff >= 0 {
+				at = strconv.FormatInt(r.lastOff, 10)
+			}
+		}
+		msg := r.message
+		if msg == "" {
+			msg = "(completed without a checkptr error)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", r.name, caught, at, r.exit, msg)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("\nAT BYTE is the offset (from the write's base pointer) of the write checkptr stopped.")
+	fmt.Println("checkptr only checks that pointer arithmetic stays inside the original allocation:")
+	fmt.Println("overflowing one field into the next field of the same object is not caught.")
+	return nil
+}
+
+func runCheckptrChild(bin, name string) checkptrResult {
+	cmd := exec.Command(bin, "scenario", name)
+	cmd.Env = append(os.Environ(), traceEnv+"=1")
+	hr := harness.Child(name, cmd)
+	r := checkptrResult{name: name, lastOff: -1, message: hr.Message, exit: hr.ExitCode}
+	r.caught = hr.Outcome == harness.FatalError && strings.Contains(hr.Message, "checkptr")
+
+	sc := bufio.NewScanner(strings.NewReader(hr.Stderr))
+	for sc.Scan() {
+		if off, ok := strings.CutPrefix(sc.Text(), tracePrefix); ok {
+			if v, err := strconv.ParseInt(off, 10, 64); err == nil {
+				r.lastOff = v
+			}
+		}
+	}
+	return r
+}
+
+// moduleDir 返回本程序所在 Go 模块的根目录（重新构建需要源码）。模块路径取自本程序的构建信息，
+// 当前目录不在这个模块里（例如在另一个模块中运行已安装的二进制）时返回错误，而不是去构建别的模块。
+func moduleDir() (string, error) {
+	path := "shijian"
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Path != "" {
+		path = bi.Main.Path
+	}
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Main}} {{.Dir}}", path).Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return "", fmt.Errorf("checkptr mode needs the go command: %w", err)
+		}
+	}
+	isMain, dir, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	if isMain != "true" || dir == "" {
+		return "", fmt.Errorf("checkptr mode must be run from inside the %s module (go-demo/)", path)
+	}
+	return dir, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"shijian/typelayout"
+)
+
+func cmdArch(args []string) error {
+	fs := newFlagSet("arch", "Type-check a Go source file offline and compare the layout of a struct type\nacross architectures (go/types gc sizes). Rows marked '*' differ.")
+	src := fs.String("src", "examples/portable.go", "Go source `file` to type-check")
+	typeName := fs.String("type", "frame", "struct type `name` in the file")
+	arches := fs.String("arch", strings.Join(typelayout.DefaultArches, ","), "comma-separated GOARCH `list`")
+	format := fs.String("format", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := typelayout.LoadSource(*src, *typeName)
+	if err != nil {
+		return err
+	}
+	c, err := typelayout.Compare(t, strings.Split(*arches, ","), typelayout.Options{})
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "table":
+		return c.WriteTable(os.Stdout)
+	case "json":
+		return c.WriteJSON(os.Stdout)
+	}
+	return fmt.Errorf("unknown -format %q (want table or json)", *format)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"shijian/audit"
+)
+
+func cmdAudit(args []string) error {
+	fs := newFlagSet("audit", "List every use of package unsafe in the packages given as arguments (default ./...) and classify it against\nthe patterns documented for unsafe.Pointer: valid, unproven (valid only if it stays\ninside the allocation) or invalid.")
+	dir := fs.String("dir", ".", "module `directory` to run go list in")
+	format := fs.String("format", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r, err := audit.Module(*dir, fs.Args()...)
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "table":
+		return r.WriteTable(os.Stdout)
+	case "json":
+		return r.WriteJSON(os.Stdout)
+	}
+	return fmt.Errorf("unknown -format %q (want table or json)", *format)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"shijian/sim"
+)
+
+func cmdCopy(args []string) error {
+	fs := newFlagSet("copy", "Copy the same source bytes into the target of a simulator layout with memcpy, strcpy, strncpy and Go's copy,\nand compare what each one writes, whether the target ends up NUL-terminated, and how far a later strlen reads.")
+	var o writeOptions
+	o.register(fs)
+	layoutPath := fs.String("layout", "", "JSON layout `file` (default: buf → canary → saved RBP → return addr)")
+	seed := fs.Int64("layout-seed", 1, "seed for layout segments initialised with \"random\"")
+	format := fs.String("format", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	src, err := o.payload()
+	if err != nil {
+		return err
+	}
+	l := sim.DefaultLayout()
+	if *layoutPath != "" {
+		if l, err = sim.LoadLayout(*layoutPath); err != nil {
+			return err
+		}
+	}
+
+	results := make([]sim.CopyResult, len(sim.CopyOps))
+	for i, op := range sim.CopyOps {
+		f := sim.NewLayoutFrame(l, rand.New(rand.NewSource(*seed)))
+		if results[i], err = f.Copy(op, src, sim.DefaultMitigations); err != nil {
+			return err
+		}
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case "table":
+	default:
+		return fmt.Errorf("unknown -format %q (want table or json)", *format)
+	}
+
+	t, _ := l.Segment(l.Target)
+	fmt.Printf("source: %d bytes % x\n", len(src), src)
+	fmt.Printf("target: %s (%d bytes) in layout %s\n\n", l.Label(l.Target), t.Size, l.Name)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OP\tWRITTEN\tPAST TARGET\tTERMINATED\tSTRLEN READS PAST\tCHANGED\tVERDICT")
+	for _, r := range results {
+		read := "no NUL in frame"
+		if n := r.ReadPast(t.Size); n >= 0 {
+			read = fmt.Sprint(n)
+		}
+		changed := "-"
+		if len(r.Changed) > 0 {
+			labels := make([]string, len(r.Changed))
+			for i, c := range r.Changed {
+				labels[i] = l.Label(c)
+			}
+			changed = strings.Join(labels, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%v\t%s\t%s\t[%s] %s\n", r.Op, r.Written, r.Past, r.Terminated, read, changed, r.Verdict.Level, r.Verdict.Text)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"shijian/typelayout"
+)
+
+func cmdLayout(args []string) error {
+	fs := newFlagSet("layout", "Print every field's offset, size and alignment of a struct type, including\nnested structs, arrays of structs and padding holes.")
+	typeName := fs.String("type", "frame", "struct `name` to report (see -list)")
+	format := fs.String("format", "table", "output format: table or json")
+	expand := fs.Int("expand", typelayout.DefaultExpandArrays, "expand at most `n` elements of struct arrays (negative: none)")
+	list := fs.Bool("list", false, "list the known types and exit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *list {
+		for _, n := range demoTypeNames() {
+			fmt.Printf("%-8s %s\n", n, demoTypes[n].desc)
+		}
+		return nil
+	}
+
+	t, err := lookupType(*typeName)
+	if err != nil {
+		return err
+	}
+	r, err := typelayout.OfWith(t, typelayout.Options{ExpandArrays: *expand})
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "table":
+		return r.WriteTable(os.Stdout)
+	case "json":
+		return r.WriteJSON(os.Stdout)
+	}
+	return fmt.Errorf("unknown -format %q (want table or json)", *format)
+}
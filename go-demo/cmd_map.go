@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"unsafe"
+
+	"shijian/frame"
+	"shijian/pattern"
+	"shijian/typelayout"
+)
+
+func cmdMap(args []string) error {
+	fs := newFlagSet("map", "Overflow a field of a struct value with the unsafe write loop and classify every\ntouched byte as a named field, inter-field padding or trailing padding.")
+	typeName := fs.String("type", "padded", "struct `name` to write into (see 'shijian layout -list')")
+	field := fs.String("field", "", "start writing at the first byte of this field `path` (default: the first field)")
+	n := fs.Int("len", 16, "number of bytes to write")
+	pat := fs.String("pattern", pattern.ModeA, "write content, as for 'shijian run -pattern'")
+	var seed uint32
+	seedFlag(fs, &seed, "seed for -pattern random")
+	format := fs.String("format", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	t, err := lookupType(*typeName)
+	if err != nil {
+		return err
+	}
+	r, err := typelayout.Of(t)
+	if err != nil {
+		return err
+	}
+	start, err := fieldOffset(r, *field)
+	if err != nil {
+		return err
+	}
+	p, err := pattern.Parse(*pat, seed, os.Stdin)
+	if err != nil {
+		return err
+	}
+	if *n < 0 {
+		return fmt.Errorf("-len must not be negative, got %d", *n)
+	}
+
+	// 在一个真实的该类型值上执行与演示相同的 unsafe 写入循环（不会超出该值本身）。
+	v := reflect.New(t)
+	obj := unsafe.Slice((*byte)(v.UnsafePointer()), t.Size())
+	before := append([]byte(nil), obj...)
+	frame.Overwrite(v.UnsafePointer(), t.Size(), int64(start), p.Bytes(*n))
+	m := r.Map(start, *n, before, obj)
+
+	switch *format {
+	case "table":
+		return m.WriteTable(os.Stdout)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	}
+	return fmt.Errorf("unknown -format %q (want table or json)", *format)
+}
+
+// fieldOffset 返回字段路径的偏移；path 为空时返回 0（第一个字段）。
+func fieldOffset(r *typelayout.Report, path string) (uintptr, error) {
+	if path == "" {
+		return 0, nil
+	}
+	for _, e := range r.Entries {
+		if e.Kind == typelayout.KindField && e.Path == path {
+			return e.Offset, nil
+		}
+	}
+	return 0, fmt.Errorf("type %s has no field %q", r.Type, path)
+}
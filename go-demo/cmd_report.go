@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+func cmdReport(args []string) error {
+	fs := newFlagSet("report", "Run the demo and write a report with the layout, payload and before/after state.")
+	var o writeOptions
+	o.register(fs)
+	out := fs.String("o", "", "write the report to `file` (default stdout)")
+	format := fs.String("format", "md", "report format: md or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	r, err := o.execute()
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "md":
+		return writeMarkdownReport(w, r)
+	}
+	return fmt.Errorf("unknown -format %q (want md or json)", *format)
+}
+
+func writeMarkdownReport(w io.Writer, r *runResult) error {
+	result := "canary unchanged"
+	if !r.Intact {
+		result = "adjacent memory was corrupted"
+	}
+	fmt.Fprintf(w, "# Overflow run report\n\n")
+	fmt.Fprintf(w, "## Layout\n\n| field | offset | size | align |\n| --- | --- | --- | --- |\n")
+	for _, f := range r.Fields {
+		fmt.Fprintf(w, "| %s | %d | %d | %d |\n", f.Name, f.Offset, f.Size, f.Align)
+	}
+	fmt.Fprintf(w, "\nframe size: %d bytes, distance &buf[0] → &canary: %d bytes\n\n", r.FrameSize, r.Distance)
+	fmt.Fprintf(w, "## Write\n\n- pattern: %s\n- requested: %d bytes from buf[0]\n- written: %d bytes\n- dropped past end of frame: %d bytes\n- payload: `% x`\n\n", r.Pattern, r.Length, r.Written, r.Dropped, r.Payload)
+	fmt.Fprintf(w, "## Byte order\n\n- host: %s\n- canary stored as: %s", r.HostOrder, r.Order)
+	if r.Simulated {
+		fmt.Fprintf(w, " (simulated)")
+	}
+	fmt.Fprintf(w, "\n\n| | canary bytes (low → high) | as little-endian | as big-endian |\n| --- | --- | --- | --- |\n")
+	fmt.Fprintf(w, "| before | `% x` | 0x%016x | 0x%016x |\n", r.DecodeBefore.Bytes[:], r.DecodeBefore.Little, r.DecodeBefore.Big)
+	fmt.Fprintf(w, "| after | `% x` | 0x%016x | 0x%016x |\n\n", r.DecodeAfter.Bytes[:], r.DecodeAfter.Little, r.DecodeAfter.Big)
+	fmt.Fprintf(w, "## Result\n\n| | canary | raw bytes |\n| --- | --- | --- |\n")
+	fmt.Fprintf(w, "| before | 0x%016x | `% x` |\n", r.CanaryBefore, r.Before.Raw[:])
+	fmt.Fprintf(w, "| after | 0x%016x | `% x` |\n\n", r.CanaryAfter, r.After.Raw[:])
+	_, err := fmt.Fprintf(w, "**%s**\n", result)
+	return err
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"shijian/frame"
+	"shijian/pattern"
+)
+
+// writeOptions 是 run / step / report 共用的写入参数。
+type writeOptions struct {
+	length  int
+	lenSet  bool
+	pattern string
+	seed    uint32
+	canary  uint64
+	endian  string
+}
+
+func (o *writeOptions) register(fs *flag.FlagSet) {
+	o.length = frame.BufSize + 8
+	o.canary = frame.DefaultCanary
+	fs.Func("len", fmt.Sprintf("number of bytes to write starting at buf[0] (default %d, or the length of a hex/base64/file pattern)", o.length), func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		o.length, o.lenSet = n, true
+		return nil
+	})
+	fs.StringVar(&o.pattern, "pattern", "", "write content: A | ABCD | random | custom:`TEXT` | hex:HEX | base64:B64 | file:PATH (file:- reads stdin);\nplain text is treated as custom (default: 16 x 'A' followed by 0xdeadbeefcafebabe in -endian order)")
+	seedFlag(fs, &o.seed, "seed for -pattern random (same generator as the web page)")
+	fs.StringVar(&o.endian, "endian", "host", "byte order of the canary and of the demo overwrite: host, little or big\n(a non-host order simulates that machine: memory holds the bytes it would hold there)")
+	fs.Func("canary", fmt.Sprintf("initial canary value (default 0x%016x)", frame.DefaultCanary), func(s string) error {
+		v, err := strconv.ParseUint(s, 0, 64)
+		if err != nil {
+			return err
+		}
+		o.canary = v
+		return nil
+	})
+}
+
+// seedFlag 定义 -seed：随机 pattern 的种子是 uint32，超出范围的值作为参数错误拒绝，而不是截断。
+func seedFlag(fs *flag.FlagSet, p *uint32, usage string) {
+	*p = pattern.DefaultSeed
+	fs.Func("seed", fmt.Sprintf("%s (default %d)", usage, pattern.DefaultSeed), func(s string) error {
+		v, err := strconv.ParseUint(s, 0, 64)
+		if err != nil {
+			return err
+		}
+		if v > math.MaxUint32 {
+			return fmt.Errorf("seed must be at most %d", uint32(math.MaxUint32))
+		}
+		*p = uint32(v)
+		return nil
+	})
+}
+
+// order 返回 -endian 对应的字节序。
+func (o *writeOptions) order() (binary.ByteOrder, error) {
+	switch o.endian {
+	case "", "host":
+		return frame.HostByteOrder(), nil
+	case "little":
+		return binary.LittleEndian, nil
+	case "big":
+		return binary.BigEndian, nil
+	}
+	return nil, fmt.Errorf("unknown -endian %q (want host, little or big)", o.endian)
+}
+
+// payload 按参数构造要写入的字节。
+func (o *writeOptions) payload() ([]byte, error) {
+	if o.length < 0 {
+		return nil, fmt.Errorf("-len must not be negative, got %d", o.length)
+	}
+	if o.pattern == "" {
+		order, err := o.order()
+		if err != nil {
+			return nil, err
+		}
+		p := make([]byte, o.length)
+		demo := frame.DemoPayloadOrder(order, frame.DefaultOverwrite)
+		for i := range p {
+			p[i] = 'A'
+			if i < len(demo) {
+				p[i] = demo[i]
+			}
+		}
+		return p, nil
+	}
+
+	pat, err := pattern.Parse(o.pattern, o.seed, os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	n := o.length
+	if lit, ok := pat.Literal(); ok && !o.lenSet && pat.Mode != pattern.ModeCustom {
+		n = len(lit)
+	}
+	return pat.Bytes(n), nil
+}
+
+// describe 返回写入内容的简短说明，用于报告。
+func (o *writeOptions) describe() string {
+	switch {
+	case o.pattern == "":
+		return fmt.Sprintf("demo (16 x 'A' + 0xdeadbeefcafebabe, %s)", o.endian)
+	case o.pattern == pattern.ModeRandom:
+		return fmt.Sprintf("random (seed %d)", o.seed)
+	}
+	return o.pattern
+}
+
+// runResult 是一次演示的结果，供 run 与 report 共用。
+type runResult struct {
+	Pattern  string         `json:"pattern"`
+	Length   int            `json:"length"`
+	Written  int            `json:"written"`
+	Dropped  int            `json:"dropped"` // 超出 frame 被截断的字节数
+	Payload  hexBytes       `json:"payload"`
+	BufAddr  uintptr        `json:"bufAddr"`
+	CanaryAt uintptr        `json:"canaryAddr"`
+	Distance uintptr        `json:"distance"`
+	Before   frame.Snapshot `json:"before"`
+	After    frame.Snapshot `json:"after"`
+	Intact   bool           `json:"canaryIntact"`
+
+	HostOrder    string        `json:"hostOrder"`
+	Order        string        `json:"order"`     // canary 使用的字节序（-endian）
+	Simulated    bool          `json:"simulated"` // Order 与宿主不同：模拟另一种字节序的机器
+	CanaryBefore uint64        `json:"canaryBefore"`
+	CanaryAfter  uint64        `json:"canaryAfter"` // 按 Order 解释
+	DecodeBefore frame.Decoded `json:"decodeBefore"`
+	DecodeAfter  frame.Decoded `json:"decodeAfter"`
+	FrameSize    int           `json:"frameSize"`
+	Fields       []frame.Field `json:"fields"`
+}
+
+// hexBytes 在 JSON 报告中以 hex 字符串输出，方便和表格里的字节对照。
+type hexBytes []byte
+
+func (b hexBytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
+
+// execute 在一个新的 frame 上执行一次写入。
+func (o *writeOptions) execute() (*runResult, error) {
+	p, err := o.payload()
+	if err != nil {
+		return nil, err
+	}
+	order, err := o.order()
+	if err != nil {
+		return nil, err
+	}
+	f := frame.NewWithOrder(o.canary, order)
+	r := &runResult{Pattern: o.describe(), Length: len(p), Payload: p, Before: f.Snapshot(), FrameSize: frame.Size, Fields: frame.Layout()}
+	r.BufAddr, r.CanaryAt = f.Addrs()
+	r.Distance = f.Distance()
+	r.HostOrder, r.Order = frame.OrderName(frame.HostByteOrder()), frame.OrderName(order)
+	r.Simulated = r.HostOrder != r.Order
+	r.CanaryBefore, r.DecodeBefore = f.CanaryIn(order), r.Before.Decode()
+
+	n, err := f.WriteAt(p, 0)
+	if err != nil && !errors.Is(err, frame.ErrOutOfFrame) {
+		return nil, err
+	}
+	r.Written, r.Dropped = n, len(p)-n
+	r.After = f.Snapshot()
+	r.Intact = f.CanaryIntact()
+	r.CanaryAfter, r.DecodeAfter = f.CanaryIn(order), r.After.Decode()
+	return r, nil
+}
+
+func cmdRun(args []string) error {
+	fs := newFlagSet("run", "Write -len bytes into a frame{buf [16]byte; canary uint64} starting at buf[0]\nand report whether the adjacent canary was corrupted.")
+	var o writeOptions
+	o.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	r, err := o.execute()
+	if err != nil {
+		return err
+	}
+
+	host := "Host  : " + r.HostOrder
+	if r.Simulated {
+		host += fmt.Sprintf(" (simulating a %s machine)", r.Order)
+	}
+	fmt.Println(host)
+	fmt.Printf("Before: canary = 0x%016x\n", r.CanaryBefore)
+	printCanaryBytes(r.DecodeBefore)
+	fmt.Printf("Layout: &buf=%#x, &canary=%#x (distance=%d bytes)\n", r.BufAddr, r.CanaryAt, r.Distance)
+	if r.Dropped > 0 {
+		fmt.Printf("Write : %d bytes requested, %d dropped past the end of the frame\n", r.Length, r.Dropped)
+	}
+	fmt.Printf("After : canary = 0x%016x\n", r.CanaryAfter)
+	printCanaryBytes(r.DecodeAfter)
+	if !r.Intact {
+		fmt.Println("Result: adjacent memory was corrupted (demo).")
+	} else {
+		fmt.Println("Result: canary unchanged.")
+	}
+	return nil
+}
+
+// printCanaryBytes 打印 canary 的原始字节以及两种字节序下的解释，
+// 解释为什么打印出的整数看起来和写入的字节顺序“相反”。
+func printCanaryBytes(d frame.Decoded) {
+	fmt.Printf("        bytes (low → high address): % x\n", d.Bytes[:])
+	fmt.Printf("        as little-endian: 0x%016x   as big-endian: 0x%016x\n", d.Little, d.Big)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"shijian/harness"
+	"shijian/scenario"
+)
+
+func cmdScenario(args []string) error {
+	fs := newFlagSet("scenario", "Run teaching scenarios by name (all of them when no name is given).\nUse -list to see the available scenarios.")
+	list := fs.Bool("list", false, "list the scenarios and exit")
+	mode := fs.String("harness", "", "run under the fault-capture harness and print a structured result per scenario:\nin-process (debug.SetPanicOnFault) or child (one child process per scenario)")
+	format := fs.String("format", "table", "harness result format: table or json")
+	checkptr := fs.Bool("checkptr", false, "rebuild with -gcflags=all=-d=checkptr and run each scenario in a child process,\nreporting whether checkptr caught it and at which byte")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: shijian scenario [flags] [name ...]\n\nRun teaching scenarios by name (all of them when no name is given).\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *list {
+		for _, s := range scenario.All() {
+			fmt.Printf("%-14s %s\n", s.Name, s.Title)
+		}
+		return nil
+	}
+
+	var run []scenario.Scenario
+	for _, name := range fs.Args() {
+		s, err := scenario.Lookup(name)
+		if err != nil {
+			return err
+		}
+		run = append(run, s)
+	}
+	if len(run) == 0 {
+		run = scenario.All()
+	}
+	if *checkptr {
+		names := make([]string, len(run))
+		for i, s := range run {
+			names[i] = s.Name
+		}
+		return runCheckptr(names)
+	}
+	if *mode != "" {
+		return runHarness(*mode, *format, run)
+	}
+	for _, s := range run {
+		fmt.Printf("=== %s: %s\n\n", s.Name, s.Title)
+		if s.Crashes && fs.NArg() == 0 {
+			fmt.Printf("skipped: this scenario crashes the process; run it by name or with -harness in-process|child\n\n")
+			continue
+		}
+		res, err := s.Run(os.Stdout)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+		state := "intact"
+		if res.Corrupted {
+			state = "corrupted"
+		}
+		fmt.Printf("--- %s: %s — %s\n\n", s.Name, state, res.Summary)
+	}
+	return nil
+}
+
+// runHarness 通过 harness 运行场景，先输出各场景的过程，最后输出结果表（或只输出 JSON）。
+func runHarness(mode, format string, run []scenario.Scenario) error {
+	if format != "table" && format != "json" {
+		return fmt.Errorf("unknown -format %q (want table or json)", format)
+	}
+	var exe string
+	switch mode {
+	case harness.ModeInProcess:
+	case harness.ModeChild:
+		var err error
+		if exe, err = os.Executable(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown -harness %q (want %s or %s)", mode, harness.ModeInProcess, harness.ModeChild)
+	}
+
+	var out io.Writer = os.Stdout
+	if format == "json" {
+		out = io.Discard
+	}
+	var results []harness.Result
+	for _, s := range run {
+		var r harness.Result
+		if mode == harness.ModeChild {
+			r = harness.Child(s.Name, exec.Command(exe, "scenario", s.Name))
+			fmt.Fprint(out, r.Output)
+			if !strings.HasSuffix(r.Output, "\n\n") {
+				fmt.Fprintln(out) // 崩溃的场景没有打印结论行
+			}
+		} else {
+			fmt.Fprintf(out, "=== %s: %s\n\n", s.Name, s.Title)
+			r = harness.InProcess(s, out)
+			fmt.Fprintln(out)
+		}
+		results = append(results, r)
+	}
+	if format == "json" {
+		return harness.WriteJSON(os.Stdout, results)
+	}
+	return harness.WriteTable(os.Stdout, results)
+}
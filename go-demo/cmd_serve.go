@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+func cmdServe(args []string) error {
+	fs := newFlagSet("serve", "Serve the web visualizer over HTTP.")
+	addr := fs.String("addr", "127.0.0.1:8000", "listen `address`")
+	dir := fs.String("dir", filepath.Join("..", "docs"), "`directory` containing index.html")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(*dir, "index.html")); err != nil {
+		return fmt.Errorf("visualizer not found (use -dir): %w", err)
+	}
+
+	fmt.Printf("Serving %s on http://%s/\n", *dir, *addr)
+	return http.ListenAndServe(*addr, http.FileServer(http.Dir(*dir)))
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+
+	"shijian/sim"
+)
+
+func cmdVectors(args []string) error {
+	fs := newFlagSet("vectors", "Check the Go simulator against sim/vectors.json (shared with docs/check-vectors.js).")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := sim.CheckVectors(); err != nil {
+		return err
+	}
+	vs, _ := sim.Vectors()
+	fmt.Printf("ok: %d vectors\n", len(vs))
+	return nil
+}
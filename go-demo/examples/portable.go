@@ -0,0 +1,29 @@
+//go:build ignore
+
+// 本文件只用于 shijian arch 的跨架构布局比较，不参与构建：
+//
+//	go run . arch -src examples/portable.go -type record
+package examples
+
+// frame 与演示中的布局相同：各架构上 canary 都在偏移 16。
+type frame struct {
+	buf    [16]byte
+	canary uint64
+}
+
+// record 在 32 位架构上 uint64 只按 4 字节对齐，int / 指针也只有 4 字节，
+// 因此 id 的偏移、next 的大小和整体大小都会变化。
+type record struct {
+	tag  byte
+	id   uint64
+	n    int
+	next *record
+}
+
+// slot 把一个 int32 夹在 uintptr 与 uint64 之间：64 位上会多出填充。
+type slot struct {
+	key   uintptr
+	hits  int32
+	stamp uint64
+	name  string
+}
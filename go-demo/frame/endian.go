@@ -0,0 +1,64 @@
+package frame
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// HostByteOrder 在运行时检测宿主机的字节序，而不是假设它是小端。
+func HostByteOrder() binary.ByteOrder {
+	x := uint16(0x0102)
+	if *(*byte)(unsafe.Pointer(&x)) == 0x02 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// OrderName 返回字节序的可读名称（"little-endian" / "big-endian"）。
+func OrderName(o binary.ByteOrder) string {
+	if o == binary.ByteOrder(binary.BigEndian) {
+		return "big-endian"
+	}
+	return "little-endian"
+}
+
+// NewWithOrder 创建一个 canary 按 order 字节序存放在内存中的 Frame。
+// order 与宿主相同时等价于 New；在小端宿主上传入 binary.BigEndian 即可模拟大端机器：
+// 内存里的字节与大端机器一致，CanaryIn(f.Order()) 读出的也是大端机器上看到的值。
+func NewWithOrder(canary uint64, order binary.ByteOrder) *Frame {
+	var b [8]byte
+	order.PutUint64(b[:], canary)
+	f := New(HostByteOrder().Uint64(b[:]))
+	f.order = order
+	return f
+}
+
+// Order 返回 frame 的 canary 字节序（New 创建的 frame 为宿主字节序）。
+func (f *Frame) Order() binary.ByteOrder { return f.order }
+
+// CanaryBytes 返回 canary 在内存中的原始字节（按地址从低到高）。
+func (f *Frame) CanaryBytes() [8]byte { return f.Snapshot().CanaryBytes() }
+
+// CanaryIn 按 order 解释 canary 的原始字节。
+func (f *Frame) CanaryIn(order binary.ByteOrder) uint64 {
+	b := f.CanaryBytes()
+	return order.Uint64(b[:])
+}
+
+// CanaryBytes 返回快照中 canary 的原始字节（按地址从低到高）。
+func (s Snapshot) CanaryBytes() [8]byte {
+	return *(*[8]byte)(unsafe.Pointer(&s.Canary))
+}
+
+// Decoded 是同一组 canary 字节分别按小端和大端解释得到的值。
+type Decoded struct {
+	Bytes  [8]byte `json:"bytes"`
+	Little uint64  `json:"little"`
+	Big    uint64  `json:"big"`
+}
+
+// Decode 返回快照中 canary 字节在两种字节序下的值。
+func (s Snapshot) Decode() Decoded {
+	b := s.CanaryBytes()
+	return Decoded{Bytes: b, Little: binary.LittleEndian.Uint64(b[:]), Big: binary.BigEndian.Uint64(b[:])}
+}
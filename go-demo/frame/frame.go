@@ -0,0 +1,190 @@
+// Package frame 把演示用的 frame（buf[16] + canary）封装成可复用的值，
+// 供 main、实验脚本和其他工具以编程方式驱动“越界写破坏相邻内存”的演示。
+//
+// 重要说明：
+//   - 越界写只发生在 Frame 自身的内存范围内（buf 之后的 canary 字段），
+//     超出整个结构体的部分会被截断并返回 ErrOutOfFrame，不会写到真正无关的内存。
+//   - 这里演示的是“相邻字段被覆盖”的现象，不涉及返回地址、利用载荷等内容。
+package frame
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"unsafe"
+)
+
+// BufSize 是演示缓冲区 buf 的长度（字节）。
+const BufSize = 16
+
+// DefaultCanary 是演示默认使用的哨兵值。
+const DefaultCanary uint64 = 0x1122334455667788
+
+// DefaultOverwrite 是演示 payload 末尾写入 canary 位置的值。
+const DefaultOverwrite uint64 = 0xdeadbeefcafebabe
+
+// mem 是真正参与越界写的内存布局：buf 后面紧跟 canary。
+type mem struct {
+	buf    [BufSize]byte
+	canary uint64 // 仅用于演示：期望它不被修改
+}
+
+// Size 是 frame 内存布局的总字节数（含可能的填充）。
+const Size = int(unsafe.Sizeof(mem{}))
+
+// ErrOutOfFrame 表示写入范围超出了整个 frame，超出部分已被丢弃。
+var ErrOutOfFrame = errors.New("frame: write extends past end of frame")
+
+// ErrNegativeOffset 表示写入起点为负数。
+var ErrNegativeOffset = errors.New("frame: negative offset")
+
+// Frame 是一个带 canary 的演示栈帧。零值不可用，请使用 New 创建。
+type Frame struct {
+	m       mem
+	initial uint64           // canary 初始值（按宿主字节序存放的原始值）
+	order   binary.ByteOrder // canary 的“逻辑”字节序，见 NewWithOrder
+}
+
+// Snapshot 是某一时刻 frame 内容的拷贝。
+type Snapshot struct {
+	Buf    [BufSize]byte
+	Canary uint64
+	Raw    [Size]byte // 按内存顺序排列的全部字节（从 &buf[0] 开始）
+}
+
+// New 创建一个 canary 初始值为 canary 的 Frame。
+func New(canary uint64) *Frame {
+	f := &Frame{initial: canary, order: HostByteOrder()}
+	f.m.canary = canary
+	return f
+}
+
+// WriteAt 从 buf[0]+off 开始逐字节写入 p，实现 io.WriterAt。
+//
+// 写入使用与最初演示相同的 unsafe 指针运算，因此超过 BufSize 的部分会
+// 覆盖 canary；超过 Size 的部分会被截断，并返回 ErrOutOfFrame。
+func (f *Frame) WriteAt(p []byte, off int64) (n int, err error) {
+	return Overwrite(unsafe.Pointer(&f.m), uintptr(Size), off, p)
+}
+
+// Overwrite 是演示写入循环的通用版本：把 p 逐字节写到 base+off 开始的内存，
+// 只在 base 指向的 size 字节对象之内写入；超出部分被截断并返回 ErrOutOfFrame。
+//
+// 对象内部的字段边界不做任何检查——这正是要演示的“越界写覆盖相邻字段”。
+func Overwrite(base unsafe.Pointer, size uintptr, off int64, p []byte) (n int, err error) {
+	if off < 0 {
+		return 0, ErrNegativeOffset
+	}
+	if off >= int64(size) {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, ErrOutOfFrame
+	}
+	if rest := int(size) - int(off); len(p) > rest {
+		p, err = p[:rest], ErrOutOfFrame
+	}
+
+	// 关键：故意越界写
+	// 从起始地址开始逐字节写入，会覆盖后面的字段（对 Frame 来说就是 buf 之后的 canary）。
+	for i := 0; i < len(p); i++ {
+		TraceWrite(off + int64(i))
+		*(*byte)(unsafe.Pointer(uintptr(base) + uintptr(off) + uintptr(i))) = p[i]
+	}
+	return len(p), err
+}
+
+// WriteHook 若非 nil，会在每个字节被写入之前以该字节相对写入基址的偏移调用。
+// checkptr 模式用它记录“写到第几个字节时被运行时拦下”。
+var WriteHook func(off int64)
+
+// TraceWrite 调用 WriteHook（如果已设置）。自己实现 unsafe 写循环的代码应在每次写入前调用它。
+func TraceWrite(off int64) {
+	if WriteHook != nil {
+		WriteHook(off)
+	}
+}
+
+// Snapshot 返回当前 frame 内容的拷贝。
+func (f *Frame) Snapshot() Snapshot {
+	s := Snapshot{Buf: f.m.buf, Canary: f.m.canary}
+	s.Raw = *(*[Size]byte)(unsafe.Pointer(&f.m))
+	return s
+}
+
+// Canary 返回 canary 的当前值（按 frame 的字节序 Order 解释，New 创建的 frame 即宿主字节序）。
+func (f *Frame) Canary() uint64 { return f.CanaryIn(f.order) }
+
+// InitialCanary 返回创建 frame 时设置的 canary 值。
+func (f *Frame) InitialCanary() uint64 {
+	b := *(*[8]byte)(unsafe.Pointer(&f.initial))
+	return f.order.Uint64(b[:])
+}
+
+// CanaryIntact 报告 canary 是否仍等于初始值。
+func (f *Frame) CanaryIntact() bool { return f.m.canary == f.initial }
+
+// Reset 清空 buf 并把 canary 恢复为初始值。
+func (f *Frame) Reset() {
+	f.m = mem{canary: f.initial}
+}
+
+// Addrs 返回 &buf[0] 与 &canary 的地址。
+func (f *Frame) Addrs() (buf, canary uintptr) {
+	return uintptr(unsafe.Pointer(&f.m.buf[0])), uintptr(unsafe.Pointer(&f.m.canary))
+}
+
+// Distance 返回 &buf[0] 到 &canary 的距离（字节）。
+func (f *Frame) Distance() uintptr {
+	buf, canary := f.Addrs()
+	return canary - buf
+}
+
+// Field 描述 frame 内存布局中的一个字段。
+type Field struct {
+	Name   string
+	Offset uintptr
+	Size   uintptr
+	Align  uintptr
+}
+
+// Layout 返回 frame 内存布局中各字段的偏移、大小与对齐。
+func Layout() []Field {
+	var m mem
+	return []Field{
+		{"buf", unsafe.Offsetof(m.buf), unsafe.Sizeof(m.buf), unsafe.Alignof(m.buf)},
+		{"canary", unsafe.Offsetof(m.canary), unsafe.Sizeof(m.canary), unsafe.Alignof(m.canary)},
+	}
+}
+
+// Type 返回 frame 内存布局对应的结构体类型，供反射类工具（如 typelayout）使用。
+func Type() reflect.Type { return reflect.TypeOf(mem{}) }
+
+// FieldAt 返回 frame 内偏移 off 处的字段名与字段内下标（如 "canary", 3）。
+// off 落在字段之间的填充或超出 frame 时返回 ("", -1)。
+func FieldAt(off int) (name string, index int) {
+	for _, f := range Layout() {
+		if uintptr(off) >= f.Offset && uintptr(off) < f.Offset+f.Size {
+			return f.Name, off - int(f.Offset)
+		}
+	}
+	return "", -1
+}
+
+// DemoPayload 构造一个“看起来像 payload”的数据：BufSize 字节 'A' 填充 + 8 字节 overwrite（小端）。
+// 在 C 的典型栈溢出里，这种“越过局部缓冲区边界继续写”的行为就是破坏的起点。
+//
+// 只有在小端宿主上，写入后 Canary() 才会读出 overwrite；需要与宿主无关时使用 DemoPayloadOrder。
+func DemoPayload(overwrite uint64) []byte {
+	return DemoPayloadOrder(binary.LittleEndian, overwrite)
+}
+
+// DemoPayloadOrder 与 DemoPayload 相同，但按 order 编码 overwrite。
+func DemoPayloadOrder(order binary.ByteOrder, overwrite uint64) []byte {
+	payload := make([]byte, BufSize+8)
+	for i := 0; i < BufSize; i++ {
+		payload[i] = 'A'
+	}
+	order.PutUint64(payload[BufSize:], overwrite)
+	return payload
+}
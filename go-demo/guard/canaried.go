@@ -0,0 +1,127 @@
+package guard
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+// CanarySize 是 Canaried 每一侧哨兵的长度（字节）。
+const CanarySize = 8
+
+// canariedMem 是 Canaried 的内存布局：与 frame 一样“数据后面紧跟 canary”，前面再加一个。
+// 两个哨兵都是 [8]byte（对齐为 1），所以 after 紧贴在 value 之后，中间没有填充——
+// 否则从 value 溢出的头几个字节会先落进填充里，不会被发现（见 map 子命令）。
+type canariedMem[T any] struct {
+	before [CanarySize]byte
+	value  T
+	after  [CanarySize]byte
+}
+
+// Canaried 把一个 T 类型的值放在两个随机哨兵中间（同一次分配）。
+// 用在要经过 unsafe 转换传递的结构体上，尽早在靠近出错位置的地方发现越界写。
+type Canaried[T any] struct {
+	m             *canariedMem[T]
+	before, after [CanarySize]byte // 哨兵的期望值，放在受保护的内存之外
+	name          string           // 非空表示已注册
+}
+
+// NewCanaried 创建一个保存 v 的 Canaried，哨兵取自 crypto/rand。
+func NewCanaried[T any](v T) (*Canaried[T], error) {
+	c := &Canaried[T]{m: &canariedMem[T]{value: v}}
+	if _, err := rand.Read(c.before[:]); err != nil {
+		return nil, fmt.Errorf("guard: read canary: %w", err)
+	}
+	if _, err := rand.Read(c.after[:]); err != nil {
+		return nil, fmt.Errorf("guard: read canary: %w", err)
+	}
+	c.m.before, c.m.after = c.before, c.after
+	return c, nil
+}
+
+// Ptr 返回被保护的值的指针，可以交给 unsafe 转换或 cgo。
+func (c *Canaried[T]) Ptr() *T { return &c.m.value }
+
+// Get 返回值的拷贝。
+func (c *Canaried[T]) Get() T { return c.m.value }
+
+// Set 替换被保护的值。
+func (c *Canaried[T]) Set(v T) { c.m.value = v }
+
+// Size 返回 T 的大小，也就是尾部哨兵相对值起点的偏移。
+func (c *Canaried[T]) Size() int { return int(unsafe.Sizeof(c.m.value)) }
+
+// Verify 检查两侧哨兵，完好时返回 nil，否则返回 *CorruptionError
+// （偏移相对值的起点：前导哨兵为 -8..-1，尾部哨兵从 Size() 开始）。
+func (c *Canaried[T]) Verify() error {
+	n := c.Size()
+	var e CorruptionError
+	if offs := diff(c.m.before[:], c.before[:], -CanarySize); offs != nil {
+		e.Damage = append(e.Damage, Damage{Side: SideLeading, Offsets: offs})
+	}
+	if offs := diff(c.m.after[:], c.after[:], n); offs != nil {
+		e.Damage = append(e.Damage, Damage{Side: SideTrailing, Offsets: offs})
+	}
+	if e.Damage == nil {
+		return nil
+	}
+	e.Len = n
+	return &e
+}
+
+// Register 以 name 登记 c，之后 VerifyAll 会检查它。重复登记同一个名字会替换旧的。
+// 不再使用时调用 Unregister，否则登记表会一直持有它。
+func (c *Canaried[T]) Register(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	if c.name != "" && registry.m[c.name] == verifier(c) {
+		delete(registry.m, c.name)
+	}
+	c.name = name
+	registry.m[name] = c
+}
+
+// Unregister 取消登记；未登记时什么也不做。
+func (c *Canaried[T]) Unregister() {
+	registry.Lock()
+	defer registry.Unlock()
+	if c.name != "" && registry.m[c.name] == verifier(c) {
+		delete(registry.m, c.name)
+	}
+	c.name = ""
+}
+
+// verifier 是登记表里保存的、与 T 无关的接口。
+type verifier interface{ Verify() error }
+
+var registry = struct {
+	sync.Mutex
+	m map[string]verifier
+}{m: map[string]verifier{}}
+
+// VerifyAll 按名字顺序检查所有已登记的 Canaried，把每个失败包装成 "name: error"，
+// 用 errors.Join 合并返回；全部完好时返回 nil。
+func VerifyAll() error {
+	registry.Lock()
+	names := make([]string, 0, len(registry.m))
+	for name := range registry.m {
+		names = append(names, name)
+	}
+	vs := make([]verifier, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		vs[i] = registry.m[name]
+	}
+	registry.Unlock()
+
+	var errs []error
+	for i, v := range vs {
+		if err := v.Verify(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", names[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
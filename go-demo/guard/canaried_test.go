@@ -0,0 +1,93 @@
+package guard
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type canariedRec struct {
+	id  uint32
+	buf [12]byte
+}
+
+func newCanaried(t *testing.T) *Canaried[canariedRec] {
+	t.Helper()
+	c, err := NewCanaried(canariedRec{id: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Unregister)
+	return c
+}
+
+func TestCanariedVerify(t *testing.T) {
+	c := newCanaried(t)
+	if err := c.Verify(); err != nil {
+		t.Fatalf("fresh Canaried: %v", err)
+	}
+	c.Ptr().buf[3] = 'x' // 值内部的写入不碰哨兵
+	if err := c.Verify(); err != nil {
+		t.Fatalf("in-bounds write: %v", err)
+	}
+
+	c.m.before[CanarySize-1] ^= 0xff // 紧挨着值起点的前导字节
+	c.m.after[0] ^= 0xff             // 紧挨着值末尾的尾部字节
+	c.m.after[2] ^= 0xff
+	err := c.Verify()
+	if !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("Verify = %v, want ErrCorrupted", err)
+	}
+	var ce *CorruptionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("Verify = %T, want *CorruptionError", err)
+	}
+	n := c.Size()
+	want := []Damage{
+		{Side: SideLeading, Offsets: []int{-1}},
+		{Side: SideTrailing, Offsets: []int{n, n + 2}},
+	}
+	if ce.Len != n || !reflect.DeepEqual(ce.Damage, want) {
+		t.Errorf("Verify = {Len: %d, Damage: %v}, want {Len: %d, Damage: %v}", ce.Len, ce.Damage, n, want)
+	}
+}
+
+func TestVerifyAll(t *testing.T) {
+	a, b := newCanaried(t), newCanaried(t)
+	a.Register("test-a")
+	b.Register("test-b")
+	if err := VerifyAll(); err != nil {
+		t.Fatalf("VerifyAll with intact canaries: %v", err)
+	}
+
+	b.m.after[0] ^= 0xff
+	err := VerifyAll()
+	if !errors.Is(err, ErrCorrupted) || !strings.Contains(err.Error(), "test-b: ") || strings.Contains(err.Error(), "test-a") {
+		t.Fatalf("VerifyAll = %v, want only test-b reported", err)
+	}
+
+	b.Unregister()
+	if err := VerifyAll(); err != nil {
+		t.Errorf("VerifyAll after Unregister: %v", err)
+	}
+}
+
+// TestRegisterReplace：同名登记替换旧的 Canaried 后，旧的改名登记不能把新的从登记表里删掉。
+func TestRegisterReplace(t *testing.T) {
+	old, repl := newCanaried(t), newCanaried(t)
+	old.Register("test-shared")
+	repl.Register("test-shared")
+	old.Register("test-old")
+
+	repl.m.after[0] ^= 0xff
+	err := VerifyAll()
+	if err == nil || !strings.Contains(err.Error(), "test-shared: ") {
+		t.Fatalf("VerifyAll = %v, want the replacement under test-shared still checked", err)
+	}
+
+	old.Unregister() // old 已经不在 test-shared 下，不能删掉 repl
+	if err := VerifyAll(); err == nil {
+		t.Error("VerifyAll = nil after unregistering the replaced wrapper, want test-shared still checked")
+	}
+}
@@ -0,0 +1,56 @@
+package guard
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// memoryErrorPrefix 是运行时不带地址的内存错误（空指针解引用）的信息前缀。
+const memoryErrorPrefix = "runtime error: invalid memory address"
+
+// Fault 描述一次被 CatchFault 捕获的内存访问错误（SIGSEGV/SIGBUS）。
+type Fault struct {
+	Addr    uintptr // 出错的地址；运行时没有提供时（例如空指针解引用）为 0，HasAddr 为 false
+	HasAddr bool
+	Message string // 运行时的 panic 信息
+}
+
+func (f *Fault) Error() string {
+	if f.HasAddr {
+		return fmt.Sprintf("fault at %#x: %s", f.Addr, f.Message)
+	}
+	return "fault: " + f.Message
+}
+
+// CatchFault 在打开 debug.SetPanicOnFault 的情况下调用 fn：fn 访问了未映射或受保护的内存时，
+// 运行时不再直接崩溃，而是 panic；CatchFault 恢复这个 panic 并以 *Fault 返回。
+// fn 正常返回时返回 nil。其他 panic（包括越界下标等非内存错误）会原样继续向上传播。
+//
+// 运行时对低于 0x1000 的地址（空指针解引用）报告的错误不带地址，这种情况同样返回 *Fault，HasAddr 为 false。
+//
+// SetPanicOnFault 只影响当前 goroutine，fn 里新启动的 goroutine 出错仍会让进程崩溃。
+func CatchFault(fn func()) (f *Fault) {
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		err, ok := r.(runtime.Error)
+		if !ok {
+			panic(r)
+		}
+		if ae, ok := err.(interface{ Addr() uintptr }); ok {
+			f = &Fault{Addr: ae.Addr(), HasAddr: true, Message: err.Error()}
+			return
+		}
+		if !strings.HasPrefix(err.Error(), memoryErrorPrefix) {
+			panic(r)
+		}
+		f = &Fault{Message: err.Error()}
+	}()
+	fn()
+	return nil
+}
@@ -0,0 +1,194 @@
+// Package guard 把演示里“buf 后面跟一个 canary”的想法做成可以在真实代码里使用的工具：
+// 在用户数据的前后各放一段随机哨兵，事后检查哨兵是否被改写，
+// 用来在 cgo 或大量使用 unsafe 的代码里尽早发现越界写。
+//
+// 与演示的 frame 不同，哨兵来自 crypto/rand，每个缓冲区各不相同，
+// 越界写很难“恰好”写回原值。
+package guard
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// DefaultSentinelSize 是每一侧哨兵的默认长度（字节）。
+const DefaultSentinelSize = 16
+
+// ErrCorrupted 表示哨兵被改写。Check 与 Verify 返回的 *CorruptionError 满足 errors.Is(err, ErrCorrupted)。
+var ErrCorrupted = errors.New("guard: sentinel corrupted")
+
+// ErrClosed 表示缓冲区已经关闭。
+var ErrClosed = errors.New("guard: buffer closed")
+
+// 哨兵所在的一侧。
+const (
+	SideLeading  = "leading"
+	SideTrailing = "trailing"
+)
+
+// Damage 描述一侧哨兵中被改写的字节。
+type Damage struct {
+	Side string `json:"side"`
+	// Offsets 是被改写字节相对数据起点的偏移：前导哨兵为负数（-1 紧挨着 data[0]），
+	// 尾部哨兵从 Len() 开始。
+	Offsets []int `json:"offsets"`
+}
+
+func (d Damage) String() string {
+	return fmt.Sprintf("%s sentinel corrupted at offsets %s", d.Side, formatRanges(d.Offsets))
+}
+
+// CorruptionError 是 Check 发现哨兵被改写时返回的错误。
+type CorruptionError struct {
+	Len    int      // 数据（或 Canaried 中值）的字节数
+	Damage []Damage // 按前导、尾部的顺序，只包含确实被改写的一侧
+}
+
+func (e *CorruptionError) Error() string {
+	parts := make([]string, len(e.Damage))
+	for i, d := range e.Damage {
+		parts[i] = d.String()
+	}
+	return fmt.Sprintf("guard: %s (%d data bytes)", strings.Join(parts, "; "), e.Len)
+}
+
+// Is 让 errors.Is(err, ErrCorrupted) 成立。
+func (e *CorruptionError) Is(target error) bool { return target == ErrCorrupted }
+
+// Options 控制 GuardedBuffer 的创建。
+type Options struct {
+	// SentinelSize 是每一侧哨兵的长度；0 表示 DefaultSentinelSize。
+	SentinelSize int
+	// CheckOnClose 为 true 时，Close 会先调用 Check 并返回它的错误。
+	CheckOnClose bool
+}
+
+// GuardedBuffer 是一段前后各有随机哨兵的字节缓冲区。
+// 哨兵和数据在同一次分配里，内存顺序为 leading | data | trailing。
+type GuardedBuffer struct {
+	mem          []byte
+	lead, trail  []byte // 哨兵的期望值
+	n            int
+	checkOnClose bool
+	closed       bool
+}
+
+// New 创建数据长度为 n 的 GuardedBuffer，使用默认选项（Close 时不检查）。
+func New(n int) (*GuardedBuffer, error) {
+	return NewWithOptions(n, Options{})
+}
+
+// NewWithOptions 按 opts 创建数据长度为 n 的 GuardedBuffer。
+func NewWithOptions(n int, opts Options) (*GuardedBuffer, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("guard: negative length %d", n)
+	}
+	s := opts.SentinelSize
+	if s == 0 {
+		s = DefaultSentinelSize
+	}
+	if s < 0 {
+		return nil, fmt.Errorf("guard: negative sentinel size %d", s)
+	}
+	b := &GuardedBuffer{
+		mem:          make([]byte, s+n+s),
+		lead:         make([]byte, s),
+		trail:        make([]byte, s),
+		n:            n,
+		checkOnClose: opts.CheckOnClose,
+	}
+	if _, err := rand.Read(b.lead); err != nil {
+		return nil, fmt.Errorf("guard: read sentinel: %w", err)
+	}
+	if _, err := rand.Read(b.trail); err != nil {
+		return nil, fmt.Errorf("guard: read sentinel: %w", err)
+	}
+	copy(b.mem, b.lead)
+	copy(b.mem[s+n:], b.trail)
+	return b, nil
+}
+
+// Bytes 返回数据部分。切片的 cap 等于 len，append 不会写进尾部哨兵；
+// 只有越过切片边界的 unsafe 写入（或 cgo）才会碰到哨兵。
+func (b *GuardedBuffer) Bytes() []byte {
+	s := len(b.lead)
+	return b.mem[s : s+b.n : s+b.n]
+}
+
+// Pointer 返回数据起点，供 cgo 或 unsafe 代码使用。n 为 0 时指向尾部哨兵的起点。
+func (b *GuardedBuffer) Pointer() unsafe.Pointer {
+	return unsafe.Add(unsafe.Pointer(unsafe.SliceData(b.mem)), len(b.lead))
+}
+
+// Len 返回数据长度。
+func (b *GuardedBuffer) Len() int { return b.n }
+
+// SentinelSize 返回每一侧哨兵的长度。
+func (b *GuardedBuffer) SentinelSize() int { return len(b.lead) }
+
+// Check 比较两侧哨兵与创建时的值，全部完好时返回 nil，
+// 否则返回 *CorruptionError，指明哪一侧、哪些偏移被改写。
+func (b *GuardedBuffer) Check() error {
+	if b.closed {
+		return ErrClosed
+	}
+	s := len(b.lead)
+	var e CorruptionError
+	if offs := diff(b.mem[:s], b.lead, -s); offs != nil {
+		e.Damage = append(e.Damage, Damage{Side: SideLeading, Offsets: offs})
+	}
+	if offs := diff(b.mem[s+b.n:], b.trail, b.n); offs != nil {
+		e.Damage = append(e.Damage, Damage{Side: SideTrailing, Offsets: offs})
+	}
+	if e.Damage == nil {
+		return nil
+	}
+	e.Len = b.n
+	return &e
+}
+
+// Close 实现 io.Closer。设置了 CheckOnClose 时先检查哨兵并返回结果；
+// 之后缓冲区不可再用（Check 返回 ErrClosed）。重复 Close 返回 nil。
+func (b *GuardedBuffer) Close() error {
+	if b.closed {
+		return nil
+	}
+	var err error
+	if b.checkOnClose {
+		err = b.Check()
+	}
+	b.closed = true
+	return err
+}
+
+// diff 返回 got 与 want 不同的下标，每个下标加上 base。
+func diff(got, want []byte, base int) []int {
+	var offs []int
+	for i := range want {
+		if got[i] != want[i] {
+			offs = append(offs, base+i)
+		}
+	}
+	return offs
+}
+
+// formatRanges 把有序的偏移压缩成 "16..19, 22" 这样的形式（偏移可能为负，所以不用 "-"）。
+func formatRanges(offs []int) string {
+	var parts []string
+	for i := 0; i < len(offs); {
+		j := i
+		for j+1 < len(offs) && offs[j+1] == offs[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, fmt.Sprint(offs[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d..%d", offs[i], offs[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
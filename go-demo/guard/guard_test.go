@@ -0,0 +1,107 @@
+package guard
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// raw 返回从数据起点往前 before 个字节开始、共 before+n+after 字节的视图，模拟越过 Bytes() 边界的 unsafe 写入。
+func raw(b *GuardedBuffer, before, after int) []byte {
+	p := unsafe.Add(b.Pointer(), -before)
+	return unsafe.Slice((*byte)(p), before+b.Len()+after)
+}
+
+func TestCheckOffsets(t *testing.T) {
+	const n = 16
+	tests := []struct {
+		name  string
+		off   int // 相对数据起点
+		wants []Damage
+	}{
+		{"in bounds", n - 1, nil},
+		{"first trailing byte", n, []Damage{{Side: SideTrailing, Offsets: []int{n}}}},
+		{"last trailing byte", n + DefaultSentinelSize - 1, []Damage{{Side: SideTrailing, Offsets: []int{n + DefaultSentinelSize - 1}}}},
+		{"last leading byte", -1, []Damage{{Side: SideLeading, Offsets: []int{-1}}}},
+		{"first leading byte", -DefaultSentinelSize, []Damage{{Side: SideLeading, Offsets: []int{-DefaultSentinelSize}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := New(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := b.SentinelSize()
+			raw(b, s, s)[s+tt.off] ^= 0xff
+			err = b.Check()
+			if tt.wants == nil {
+				if err != nil {
+					t.Fatalf("Check = %v, want nil", err)
+				}
+				return
+			}
+			var ce *CorruptionError
+			if !errors.As(err, &ce) || !errors.Is(err, ErrCorrupted) {
+				t.Fatalf("Check = %v, want *CorruptionError", err)
+			}
+			if ce.Len != n || !reflect.DeepEqual(ce.Damage, tt.wants) {
+				t.Errorf("Check = {Len: %d, Damage: %v}, want {Len: %d, Damage: %v}", ce.Len, ce.Damage, n, tt.wants)
+			}
+		})
+	}
+}
+
+func TestCheckBothSides(t *testing.T) {
+	b, err := NewWithOptions(4, Options{SentinelSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := raw(b, 4, 4)
+	r[1] ^= 0xff // 偏移 -3
+	for i := 8; i < 11; i++ {
+		r[i] ^= 0xff // 偏移 4..6
+	}
+	want := "guard: leading sentinel corrupted at offsets -3; trailing sentinel corrupted at offsets 4..6 (4 data bytes)"
+	if err := b.Check(); err == nil || err.Error() != want {
+		t.Errorf("Check = %v, want %q", err, want)
+	}
+}
+
+func TestCheckOnClose(t *testing.T) {
+	tests := []struct {
+		name         string
+		checkOnClose bool
+		overflow     bool
+		wantErr      bool
+	}{
+		{"in-bounds write, CheckOnClose", true, false, false},
+		{"out-of-bounds write, CheckOnClose", true, true, true},
+		{"out-of-bounds write, no CheckOnClose", false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewWithOptions(8, Options{CheckOnClose: tt.checkOnClose})
+			if err != nil {
+				t.Fatal(err)
+			}
+			copy(b.Bytes(), "12345678")
+			if tt.overflow {
+				copy(raw(b, 0, 1), "123456789")
+			}
+			err = b.Close()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Close = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrCorrupted) {
+				t.Errorf("Close = %v, want ErrCorrupted", err)
+			}
+			if err := b.Close(); err != nil {
+				t.Errorf("second Close = %v, want nil", err)
+			}
+			if err := b.Check(); !errors.Is(err, ErrClosed) {
+				t.Errorf("Check after Close = %v, want ErrClosed", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package guard
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrPagesUnsupported 表示当前平台不支持保护页分配。
+var ErrPagesUnsupported = errors.New("guard: guard pages are only supported on Linux")
+
+// PageBuffer 是一段末尾紧贴着 PROT_NONE 保护页的缓冲区：
+// 越过末尾写入的第一个字节就会触发缺页错误，而不是像演示里的 canary 那样悄悄写坏邻居。
+// 配合 CatchFault，这个错误会变成可以恢复的 panic，并带有出错地址。
+//
+// 数据放在最后一个可写页的末尾，因此长度不是 8 的倍数时起点不按 8 字节对齐；
+// 只有末尾一侧受保护，越过起点向前写不会被发现。
+type PageBuffer struct {
+	mem   []byte // 整个映射（含保护页）
+	data  []byte
+	guard uintptr // 保护页的起始地址
+}
+
+// Bytes 返回数据部分（cap 等于 len）。
+func (b *PageBuffer) Bytes() []byte { return b.data }
+
+// Len 返回数据长度。
+func (b *PageBuffer) Len() int { return len(b.data) }
+
+// Pointer 返回数据起点，供 unsafe 代码或 cgo 使用。
+func (b *PageBuffer) Pointer() unsafe.Pointer {
+	return unsafe.Pointer(unsafe.SliceData(b.data))
+}
+
+// GuardAddr 返回保护页的起始地址，也就是数据末尾之后的第一个字节。
+func (b *PageBuffer) GuardAddr() uintptr { return b.guard }
+
+// Offset 把一个地址换算成相对数据起点的偏移，用来解释 Fault.Addr。
+func (b *PageBuffer) Offset(addr uintptr) int { return int(addr - (b.guard - uintptr(len(b.data)))) }
+
+// Close 解除映射。之后再访问数据同样会触发缺页错误。
+func (b *PageBuffer) Close() error {
+	if b.mem == nil {
+		return nil
+	}
+	err := unmap(b.mem)
+	b.mem, b.data = nil, nil
+	return err
+}
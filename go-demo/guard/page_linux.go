@@ -0,0 +1,35 @@
+//go:build linux
+
+package guard
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// NewPageBuffer 用 mmap 分配 n 字节，使其末尾紧贴一个 PROT_NONE 保护页。
+func NewPageBuffer(n int) (*PageBuffer, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("guard: negative length %d", n)
+	}
+	ps := os.Getpagesize()
+	pages := (n + ps - 1) / ps
+	mem, err := syscall.Mmap(-1, 0, (pages+1)*ps, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, fmt.Errorf("guard: mmap: %w", err)
+	}
+	end := pages * ps
+	if err := syscall.Mprotect(mem[end:], syscall.PROT_NONE); err != nil {
+		syscall.Munmap(mem)
+		return nil, fmt.Errorf("guard: mprotect: %w", err)
+	}
+	return &PageBuffer{
+		mem:   mem,
+		data:  mem[end-n : end : end],
+		guard: uintptr(unsafe.Pointer(unsafe.SliceData(mem))) + uintptr(end),
+	}, nil
+}
+
+func unmap(mem []byte) error { return syscall.Munmap(mem) }
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package guard
+
+// NewPageBuffer 在非 Linux 平台上返回 ErrPagesUnsupported。
+func NewPageBuffer(n int) (*PageBuffer, error) {
+	return nil, ErrPagesUnsupported
+}
+
+func unmap([]byte) error { return nil }
@@ -0,0 +1,104 @@
+package guard
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"unsafe"
+)
+
+// ErrModified 表示封存后的区域被改写。Region.Verify 返回的 *ModifiedError 满足 errors.Is(err, ErrModified)。
+var ErrModified = errors.New("guard: sealed region modified")
+
+// ErrNotSealed 表示区域从未封存，或加入新字段后还没有重新封存。
+var ErrNotSealed = errors.New("guard: region not sealed")
+
+// Region 对一组字段各自保存一个 CRC32，由显式的 Seal / Verify 调用计算和比较。
+//
+// 与 canary 不同，它不需要越界写“经过”某个哨兵：只要字段被登记，写到哪里都能发现，
+// 并且能指出是哪些字段变了。代价是只在调用 Verify 时检查，Seal 之前发生的改写会被当成基准接受；
+// CRC32 也不是密码学哈希，只用于发现意外改写，挡不住有意伪造。
+type Region struct {
+	fields []regionField
+	sealed bool
+}
+
+type regionField struct {
+	name string
+	mem  []byte
+	sum  uint32
+}
+
+// FieldChange 是一个在封存后被改写的字段。
+type FieldChange struct {
+	Name    string `json:"name"`
+	Sealed  uint32 `json:"sealed"`  // Seal 时的 CRC32
+	Current uint32 `json:"current"` // Verify 时的 CRC32
+}
+
+// ModifiedError 是 Verify 发现字段被改写时返回的错误，Fields 按登记顺序排列。
+type ModifiedError struct {
+	Fields []FieldChange
+}
+
+func (e *ModifiedError) Error() string {
+	names := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		names[i] = f.Name
+	}
+	return fmt.Sprintf("guard: sealed region modified: %s changed", strings.Join(names, ", "))
+}
+
+// Is 让 errors.Is(err, ErrModified) 成立。
+func (e *ModifiedError) Is(target error) bool { return target == ErrModified }
+
+// NewRegion 创建一个空的区域。
+func NewRegion() *Region { return &Region{} }
+
+// AddBytes 把 b 作为名为 name 的字段登记到区域里。b 不会被拷贝：之后对它底层内存的任何改写
+// 都会在 Verify 时被发现。登记后区域需要重新 Seal。
+func (r *Region) AddBytes(name string, b []byte) {
+	r.fields = append(r.fields, regionField{name: name, mem: b})
+	r.sealed = false
+}
+
+// AddField 把 *p 占用的全部字节（包括 T 内部的填充）作为名为 name 的字段登记到 r。
+func AddField[T any](r *Region, name string, p *T) {
+	r.AddBytes(name, unsafe.Slice((*byte)(unsafe.Pointer(p)), unsafe.Sizeof(*p)))
+}
+
+// Fields 按登记顺序返回字段名。
+func (r *Region) Fields() []string {
+	names := make([]string, len(r.fields))
+	for i, f := range r.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Seal 以字段的当前内容为基准重新计算所有 CRC32。
+func (r *Region) Seal() {
+	for i := range r.fields {
+		r.fields[i].sum = crc32.ChecksumIEEE(r.fields[i].mem)
+	}
+	r.sealed = true
+}
+
+// Verify 重新计算每个字段的 CRC32 并与 Seal 时的值比较：全部一致时返回 nil，
+// 否则返回 *ModifiedError，列出变化的字段；未封存时返回 ErrNotSealed。
+func (r *Region) Verify() error {
+	if !r.sealed {
+		return ErrNotSealed
+	}
+	var e ModifiedError
+	for _, f := range r.fields {
+		if sum := crc32.ChecksumIEEE(f.mem); sum != f.sum {
+			e.Fields = append(e.Fields, FieldChange{Name: f.name, Sealed: f.sum, Current: sum})
+		}
+	}
+	if e.Fields == nil {
+		return nil
+	}
+	return &e
+}
@@ -0,0 +1,106 @@
+package guard
+
+import (
+	"errors"
+	"hash/crc32"
+	"testing"
+)
+
+type regionHeader struct {
+	kind uint8
+	size uint32
+}
+
+type regionState struct {
+	hdr   regionHeader
+	count int64
+	name  [8]byte
+	tail  []byte
+}
+
+func newRegion(s *regionState) *Region {
+	r := NewRegion()
+	AddField(r, "hdr", &s.hdr)
+	AddField(r, "count", &s.count)
+	AddField(r, "name", &s.name)
+	r.AddBytes("tail", s.tail)
+	return r
+}
+
+func TestRegionSingleField(t *testing.T) {
+	fields := []struct {
+		name    string
+		corrupt func(s *regionState)
+	}{
+		{"hdr", func(s *regionState) { s.hdr.size = 99 }},
+		{"count", func(s *regionState) { s.count++ }},
+		{"name", func(s *regionState) { s.name[7] = 'x' }},
+		{"tail", func(s *regionState) { s.tail[0] ^= 0xff }},
+	}
+	for _, f := range fields {
+		t.Run(f.name, func(t *testing.T) {
+			s := &regionState{hdr: regionHeader{kind: 1, size: 16}, count: 3, tail: make([]byte, 4)}
+			copy(s.name[:], "shijian")
+			r := newRegion(s)
+			r.Seal()
+			if err := r.Verify(); err != nil {
+				t.Fatalf("Verify right after Seal = %v", err)
+			}
+
+			f.corrupt(s)
+			err := r.Verify()
+			var me *ModifiedError
+			if !errors.As(err, &me) || !errors.Is(err, ErrModified) {
+				t.Fatalf("Verify = %v, want *ModifiedError", err)
+			}
+			if len(me.Fields) != 1 || me.Fields[0].Name != f.name {
+				t.Fatalf("Verify reported %v, want only %s", me.Fields, f.name)
+			}
+			if me.Fields[0].Sealed == me.Fields[0].Current {
+				t.Errorf("FieldChange %+v: sealed and current CRC32 are equal", me.Fields[0])
+			}
+		})
+	}
+}
+
+func TestRegionResealAfterUpdate(t *testing.T) {
+	s := &regionState{count: 1, tail: []byte("abcd")}
+	r := newRegion(s)
+	r.Seal()
+
+	// 合法的更新：改写后重新封存，新内容成为基准。
+	s.count = 2
+	copy(s.tail, "wxyz")
+	if err := r.Verify(); !errors.Is(err, ErrModified) {
+		t.Fatalf("Verify before re-seal = %v, want ErrModified", err)
+	}
+	r.Seal()
+	if err := r.Verify(); err != nil {
+		t.Fatalf("Verify after re-seal = %v, want nil", err)
+	}
+	s.count = 3
+	if err := r.Verify(); err == nil || err.Error() != "guard: sealed region modified: count changed" {
+		t.Errorf("Verify after a later write = %v, want count changed", err)
+	}
+}
+
+func TestRegionNotSealed(t *testing.T) {
+	r := NewRegion()
+	if err := r.Verify(); !errors.Is(err, ErrNotSealed) {
+		t.Fatalf("Verify on a new region = %v, want ErrNotSealed", err)
+	}
+	buf := []byte("data")
+	r.AddBytes("buf", buf)
+	r.Seal()
+	r.AddBytes("more", []byte("x"))
+	if err := r.Verify(); !errors.Is(err, ErrNotSealed) {
+		t.Fatalf("Verify after AddBytes = %v, want ErrNotSealed", err)
+	}
+	r.Seal()
+	if err := r.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.fields[0].sum; got != crc32.ChecksumIEEE(buf) {
+		t.Errorf("sealed sum of buf = %08x, want crc32 %08x", got, crc32.ChecksumIEEE(buf))
+	}
+}
@@ -0,0 +1,222 @@
+// Package harness 运行教学场景并总是返回结构化的结果，即使场景让进程崩溃：
+// 写只读内存、写未映射内存这类场景在普通运行下会直接终止进程，
+// 这里要么在进程内打开 debug.SetPanicOnFault 把错误变成 panic，要么放到子进程里运行并解析运行时的输出。
+package harness
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"shijian/scenario"
+)
+
+// Outcome 是场景的结局。
+type Outcome string
+
+const (
+	Completed   Outcome = "completed"    // 正常结束，数据完好
+	Corrupted   Outcome = "corrupted"    // 正常结束，但有数据被改写
+	Panicked    Outcome = "panicked"     // panic（进程内模式下，内存错误也归为这一类并带有地址）
+	FatalSignal Outcome = "fatal signal" // 子进程被信号终止（运行时的 SIGSEGV/SIGBUS 崩溃或外部信号）
+	FatalError  Outcome = "fatal error"  // 运行时 fatal error 但没有信号，例如 checkptr
+	Failed      Outcome = "error"        // 场景自己返回了错误，或子进程无法启动
+)
+
+// 运行方式。
+const (
+	ModeInProcess = "in-process"
+	ModeChild     = "child"
+)
+
+// Result 是一次运行的结构化结果。
+type Result struct {
+	Scenario string  `json:"scenario"`
+	Mode     string  `json:"mode"`
+	Outcome  Outcome `json:"outcome"`
+	Summary  string  `json:"summary,omitempty"` // 场景自己的结论（正常结束时）
+	Message  string  `json:"message,omitempty"` // 运行时的 panic / fatal error 信息，或错误
+	Signal   string  `json:"signal,omitempty"`  // 例如 SIGSEGV
+	Addr     uintptr `json:"addr,omitempty"`    // 出错地址（HasAddr 为 true 时有效）
+	HasAddr  bool    `json:"hasAddr"`
+	ExitCode int     `json:"exitCode"` // 仅子进程模式
+	Output   string  `json:"output"`   // 场景写出的内容（子进程模式为其标准输出）
+	Stderr   string  `json:"stderr,omitempty"`
+}
+
+// InProcess 在当前进程里运行 s，场景输出同时写到 w。
+// 运行期间打开 debug.SetPanicOnFault，所以访问只读或未映射内存会变成可恢复的 panic，
+// 结果的 Outcome 为 Panicked，Addr 是出错地址。fn 里另起的 goroutine 不受保护。
+func InProcess(s scenario.Scenario, w io.Writer) (r Result) {
+	r = Result{Scenario: s.Name, Mode: ModeInProcess}
+	var out bytes.Buffer
+	defer func() { r.Output = out.String() }()
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		p := recover()
+		if p == nil {
+			return
+		}
+		r.Outcome, r.Message = Panicked, fmt.Sprint(p)
+		if ae, ok := p.(interface{ Addr() uintptr }); ok {
+			r.Addr, r.HasAddr = ae.Addr(), true
+		}
+	}()
+
+	res, err := s.Run(io.MultiWriter(w, &out))
+	switch {
+	case err != nil:
+		r.Outcome, r.Message = Failed, err.Error()
+	case res.Corrupted:
+		r.Outcome, r.Summary = Corrupted, res.Summary
+	default:
+		r.Outcome, r.Summary = Completed, res.Summary
+	}
+	return r
+}
+
+// Child 运行 cmd（应当是 "shijian scenario <name>" 这样只运行一个场景的命令），
+// 从它的退出状态、标准输出里的 "--- name: state — summary" 行以及标准错误里运行时的
+// panic / fatal error 信息得出结果。cmd 的 Stdout、Stderr 会被替换。
+func Child(name string, cmd *exec.Cmd) Result {
+	r := Result{Scenario: name, Mode: ModeChild}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	r.Output, r.Stderr = stdout.String(), stderr.String()
+
+	var ee *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &ee):
+		r.ExitCode = ee.ExitCode()
+		if ws, ok := ee.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			r.Outcome, r.Signal = FatalSignal, signalName(ws.Signal())
+		}
+	default:
+		r.Outcome, r.Message = Failed, err.Error()
+		return r
+	}
+
+	parseStdout(&r, r.Output, name)
+	parseStderr(&r, r.Stderr)
+	if r.Outcome == "" {
+		// 非零退出但没有可识别的运行时输出：通常是场景返回了错误。
+		r.Outcome, r.Message = Failed, lastLine(r.Stderr)
+	}
+	return r
+}
+
+// parseStdout 识别 cmdScenario 打印的结论行。
+func parseStdout(r *Result, out, name string) {
+	prefix := "--- " + name + ": "
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		line, ok := strings.CutPrefix(sc.Text(), prefix)
+		if !ok {
+			continue
+		}
+		state, summary, _ := strings.Cut(line, " — ")
+		r.Summary = summary
+		if r.Outcome == "" {
+			r.Outcome = Completed
+			if state == "corrupted" {
+				r.Outcome = Corrupted
+			}
+		}
+	}
+}
+
+var (
+	// [signal SIGSEGV: segmentation violation code=0x2 addr=0x4b1c20 pc=0x48f1d4]
+	signalRE = regexp.MustCompile(`^\[signal (\w+)(?:: [^]]*?)?(?: addr=(0x[0-9a-f]+))?(?: pc=0x[0-9a-f]+)?\]`)
+	// unexpected fault address 0x4b1c20
+	faultRE = regexp.MustCompile(`^unexpected fault address (0x[0-9a-f]+)`)
+)
+
+// parseStderr 识别 Go 运行时崩溃时打印的 panic、fatal error、signal 与 fault address 行。
+func parseStderr(r *Result, stderr string) {
+	sc := bufio.NewScanner(strings.NewReader(stderr))
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "panic: ") && r.Message == "":
+			r.Outcome, r.Message = Panicked, strings.TrimPrefix(line, "panic: ")
+		case strings.HasPrefix(line, "fatal error: ") && r.Message == "":
+			r.Outcome, r.Message = FatalError, strings.TrimPrefix(line, "fatal error: ")
+		case faultRE.MatchString(line) && !r.HasAddr:
+			r.Addr, r.HasAddr = parseAddr(faultRE.FindStringSubmatch(line)[1])
+		case signalRE.MatchString(line) && r.Signal == "":
+			m := signalRE.FindStringSubmatch(line)
+			r.Signal = m[1]
+			if r.Outcome == FatalError {
+				r.Outcome = FatalSignal
+			}
+			if m[2] != "" && !r.HasAddr {
+				r.Addr, r.HasAddr = parseAddr(m[2])
+			}
+		}
+	}
+}
+
+func parseAddr(s string) (uintptr, bool) {
+	v, err := strconv.ParseUint(s, 0, 64)
+	return uintptr(v), err == nil
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
+
+func signalName(sig syscall.Signal) string {
+	switch sig {
+	case syscall.SIGSEGV:
+		return "SIGSEGV"
+	case syscall.SIGBUS:
+		return "SIGBUS"
+	case syscall.SIGABRT:
+		return "SIGABRT"
+	case syscall.SIGKILL:
+		return "SIGKILL"
+	}
+	return sig.String()
+}
+
+// WriteTable 以对齐的文本表格输出结果。
+func WriteTable(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCENARIO\tMODE\tOUTCOME\tSIGNAL\tADDRESS\tDETAIL")
+	for _, r := range results {
+		sig, addr := "-", "-"
+		if r.Signal != "" {
+			sig = r.Signal
+		}
+		if r.HasAddr {
+			addr = fmt.Sprintf("%#x", r.Addr)
+		}
+		detail := r.Message
+		if detail == "" {
+			detail = r.Summary
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Scenario, r.Mode, r.Outcome, sig, addr, detail)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON 以缩进的 JSON 数组输出结果。
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
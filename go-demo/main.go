@@ -1,50 +1,92 @@
 package main
 
 import (
-	"encoding/binary"
+	"errors"
+	"flag"
 	"fmt"
-	"unsafe"
+	"os"
+	"strings"
 )
 
 // 重要说明：
 // - Go 语言本身对数组/切片访问有边界检查，正常代码不会出现传统 C 那种“栈缓冲区溢出”。
 // - 这里用 unsafe 演示“越界写会破坏相邻内存”的现象（覆盖一个哨兵值），用于理解原理。
 // - 该示例不展示也不指导如何覆盖返回地址、构造利用载荷、绕过防护等可直接用于攻击的内容。
+// - 具体的写入逻辑在 shijian/frame 包中，这里只负责解析子命令和打印。
 
-type frame struct {
-	buf    [16]byte
-	canary uint64 // 仅用于演示：期望它不被修改
+// command 是一个子命令。run 收到的是子命令名之后的参数。
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"run", "run the overflow demo once (default)", cmdRun},
+		{"layout", "print the memory layout of a struct type", cmdLayout},
+		{"arch", "compare a struct's layout across architectures", cmdArch},
+		{"map", "classify every byte of an overflow as field or padding", cmdMap},
+		{"scenario", "run teaching scenarios (records, ...)", cmdScenario},
+		{"step", "walk the write byte by byte", cmdStep},
+		{"serve", "host the web visualizer (docs/)", cmdServe},
+		{"report", "write a run report (markdown or JSON)", cmdReport},
+		{"vectors", "check the simulator against the shared test vectors", cmdVectors},
+		{"copy", "compare memcpy, strcpy, strncpy and Go copy in the simulator", cmdCopy},
+		{"canary", "compare canary strategies across patterns and copy semantics", cmdCanary},
+		{"audit", "classify every unsafe use against the unsafe.Pointer rules", cmdAudit},
+	}
 }
 
 func main() {
-	var f frame
-	f.canary = 0x1122334455667788
-
-	fmt.Printf("Before: canary = 0x%016x\n", f.canary)
-	fmt.Printf("Layout: &buf=%p, &canary=%p (distance=%d bytes)\n",
-		&f.buf[0], &f.canary, uintptr(unsafe.Pointer(&f.canary))-uintptr(unsafe.Pointer(&f.buf[0])),
-	)
-
-	// 构造一个“看起来像 payload”的数据：16 字节填充 + 8 字节新 canary 值。
-	// 在 C 的典型栈溢出里，这种“越过局部缓冲区边界继续写”的行为就是破坏的起点。
-	payload := make([]byte, 16+8)
-	for i := 0; i < 16; i++ {
-		payload[i] = 'A'
+	enableWriteTrace()
+	args := os.Args[1:]
+	name := "run"
+	if len(args) > 0 {
+		switch args[0] {
+		case "-h", "-help", "--help", "help":
+			usage()
+			return
+		}
+		if !strings.HasPrefix(args[0], "-") {
+			name, args = args[0], args[1:]
+		}
 	}
-	binary.LittleEndian.PutUint64(payload[16:], 0xdeadbeefcafebabe)
 
-	// 关键：故意越界写
-	// 我们把 payload 从 buf 起始地址开始逐字节写入，会覆盖 buf 后面的字段（这里就是 canary）。
-	base := (*byte)(unsafe.Pointer(&f.buf[0]))
-	for i := 0; i < len(payload); i++ {
-		*(*byte)(unsafe.Pointer(uintptr(unsafe.Pointer(base)) + uintptr(i))) = payload[i]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		if err := c.run(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "shijian %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
 	}
+	fmt.Fprintf(os.Stderr, "shijian: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
 
-	fmt.Printf("After : canary = 0x%016x\n", f.canary)
-	if f.canary != 0x1122334455667788 {
-		fmt.Println("Result: adjacent memory was corrupted (demo).")
-	} else {
-		fmt.Println("Result: canary unchanged (unexpected for this demo).")
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: shijian <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.summary)
 	}
+	fmt.Fprintf(os.Stderr, "\nRun 'shijian <command> --help' for the flags of a command.\n")
 }
 
+// newFlagSet 创建带统一 usage 输出的子命令 FlagSet。
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: shijian %s [flags]\n\n%s\n\nFlags:\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -0,0 +1,146 @@
+// Package pattern 提供与网页 docs/app.js 中 mkPatternBytes 相同的写入内容生成器，
+// 并额外支持 hex / base64 字面量以及从文件或标准输入读取字节。
+//
+// 伪随机模式使用与网页相同的 mulberry32 算法和种子，
+// 因此在 Go 里可以逐字节复现学生在浏览器中看到的内容。
+package pattern
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// 模式名，前四个与网页下拉框的 value 一致。
+const (
+	ModeA      = "A"      // 全 A（0x41）
+	ModeABCD   = "ABCD"   // ABCD 循环
+	ModeRandom = "random" // 伪随机字节（mulberry32，可设种子）
+	ModeCustom = "custom" // 自定义 ASCII，循环使用
+	ModeHex    = "hex"    // hex 字面量
+	ModeBase64 = "base64" // base64 字面量
+	ModeFile   = "file"   // 文件内容；路径为 "-" 时读标准输入
+)
+
+// DefaultSeed 是网页种子输入框的默认值。
+const DefaultSeed uint32 = 1
+
+// Pattern 是一个可生成任意长度写入内容的模式。
+type Pattern struct {
+	Mode string
+	Seed uint32 // 仅 ModeRandom 使用
+	data []byte // custom / hex / base64 / file 的字节
+}
+
+// Parse 解析模式描述：
+//
+//	A | ABCD | random | custom:<ASCII> | hex:<hex> | base64:<b64> | file:<path>
+//
+// 不带已知前缀的其他文本按 custom 处理。file:- 从 stdin 读取。
+func Parse(spec string, seed uint32, stdin io.Reader) (*Pattern, error) {
+	switch spec {
+	case ModeA, ModeABCD, ModeRandom:
+		return &Pattern{Mode: spec, Seed: seed}, nil
+	}
+	mode, arg, ok := strings.Cut(spec, ":")
+	if !ok {
+		mode, arg = ModeCustom, spec
+	}
+	p := &Pattern{Mode: mode, Seed: seed}
+	var err error
+	switch mode {
+	case ModeCustom:
+		p.data = customBytes(arg)
+	case ModeHex:
+		p.data, err = hex.DecodeString(strings.Join(strings.Fields(arg), ""))
+	case ModeBase64:
+		p.data, err = base64.StdEncoding.DecodeString(arg)
+	case ModeFile:
+		if arg == "-" {
+			p.data, err = io.ReadAll(stdin)
+		} else {
+			p.data, err = os.ReadFile(arg)
+		}
+	default:
+		p.Mode, p.data = ModeCustom, customBytes(spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pattern %s: %w", mode, err)
+	}
+	if p.Mode != ModeCustom && len(p.data) == 0 {
+		return nil, errors.New("pattern " + mode + ": no bytes")
+	}
+	return p, nil
+}
+
+// customBytes 与网页一致：每个字符取一个字节，即其第一个 UTF-16 码元的低 8 位。
+func customBytes(s string) []byte {
+	var b []byte
+	for _, r := range s {
+		if hi, _ := utf16.EncodeRune(r); hi != utf8.RuneError {
+			r = hi
+		}
+		b = append(b, byte(r))
+	}
+	return b
+}
+
+// Literal 返回 custom / hex / base64 / file 模式的原始字节；其他模式返回 false。
+func (p *Pattern) Literal() ([]byte, bool) {
+	if p.Mode == ModeA || p.Mode == ModeABCD || p.Mode == ModeRandom {
+		return nil, false
+	}
+	return p.data, true
+}
+
+// Bytes 生成 n 个字节。字面量短于 n 时循环使用；custom 为空时与网页一样使用 '?'。
+func (p *Pattern) Bytes(n int) []byte {
+	out := make([]byte, n)
+	switch p.Mode {
+	case ModeA:
+		for i := range out {
+			out[i] = 0x41
+		}
+	case ModeABCD:
+		for i := range out {
+			out[i] = "ABCD"[i%4]
+		}
+	case ModeRandom:
+		r := NewRand(p.Seed)
+		for i := range out {
+			out[i] = r.Byte()
+		}
+	default:
+		if len(p.data) == 0 {
+			for i := range out {
+				out[i] = 0x3f // '?'
+			}
+			break
+		}
+		for i := range out {
+			out[i] = p.data[i%len(p.data)]
+		}
+	}
+	return out
+}
+
+// String 返回可以再次传给 Parse 的描述（file 模式除外，只保留模式名）。
+func (p *Pattern) String() string {
+	switch p.Mode {
+	case ModeA, ModeABCD, ModeFile:
+		return p.Mode
+	case ModeRandom:
+		return fmt.Sprintf("random (seed %d)", p.Seed)
+	case ModeHex:
+		return "hex:" + hex.EncodeToString(p.data)
+	case ModeBase64:
+		return "base64:" + base64.StdEncoding.EncodeToString(p.data)
+	}
+	return "custom:" + string(p.data)
+}
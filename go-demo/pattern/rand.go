@@ -0,0 +1,26 @@
+package pattern
+
+// Rand 是 mulberry32 伪随机数生成器，docs/app.js 中有逐位相同的实现。
+// 它只用于复现演示内容，不适合任何安全用途。
+type Rand struct {
+	state uint32
+}
+
+// NewRand 返回以 seed 为种子的生成器。
+func NewRand(seed uint32) *Rand {
+	return &Rand{state: seed}
+}
+
+// Uint32 返回下一个 32 位伪随机数。
+func (r *Rand) Uint32() uint32 {
+	r.state += 0x6d2b79f5
+	t := r.state
+	t = (t ^ t>>15) * (t | 1)
+	t ^= t + (t^t>>7)*(t|61)
+	return t ^ t>>14
+}
+
+// Byte 返回下一个伪随机字节（取高 8 位，与网页相同）。
+func (r *Rand) Byte() byte {
+	return byte(r.Uint32() >> 24)
+}
@@ -0,0 +1,56 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"shijian/frame"
+	"shijian/guard"
+)
+
+func init() {
+	Register(Scenario{
+		Name:  "canaried",
+		Title: "guard.Canaried[T]: sentinels around any value, checked with VerifyAll",
+		Run:   runCanaried,
+	})
+}
+
+// userRecord 是一个会被当成字节数组经 unsafe 传递的结构体。
+type userRecord struct {
+	name [10]byte
+	id   uint16
+}
+
+func runCanaried(w io.Writer) (Result, error) {
+	a, err := guard.NewCanaried(userRecord{id: 1})
+	if err != nil {
+		return Result{}, err
+	}
+	b, err := guard.NewCanaried(userRecord{id: 2})
+	if err != nil {
+		return Result{}, err
+	}
+	a.Register("alice")
+	b.Register("bob")
+	defer a.Unregister()
+	defer b.Unregister()
+	fmt.Fprintf(w, "two Canaried[userRecord] registered; value size %d, trailing canary at offset %d\n", a.Size(), a.Size())
+
+	// 把 bob 的名字当成 C 字符串写进 name，没有检查长度：14 字节写进 10 字节的 name。
+	name := []byte("bob the builder")[:14]
+	if _, err := frame.Overwrite(unsafe.Pointer(b.Ptr()), uintptr(b.Size()+guard.CanarySize), 0, name); err != nil {
+		return Result{}, err
+	}
+	fmt.Fprintf(w, "wrote %q (%d bytes) into bob.name [10]byte through unsafe\n", name, len(name))
+	fmt.Fprintf(w, "bob.id is now %d (was 2): the first overflowing bytes hit the next field\n", b.Get().id)
+
+	err = guard.VerifyAll()
+	fmt.Fprintf(w, "VerifyAll: %v\n", err)
+	if errors.Is(err, guard.ErrCorrupted) {
+		return Result{Corrupted: true, Summary: "VerifyAll named the corrupted wrapper and the overwritten canary bytes"}, nil
+	}
+	return Result{Summary: "all canaries intact"}, err
+}
@@ -0,0 +1,122 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"unsafe"
+
+	"shijian/frame"
+	"shijian/guard"
+)
+
+func init() {
+	Register(Scenario{
+		Name:  "checksum",
+		Title: "guard.Region (CRC32 per field) vs the frame canary: writes that skip or miss the canary",
+		Run:   runChecksum,
+	})
+}
+
+// checkedFrame 是 frame 的 buf → canary 再加上前后各一个字段：
+// owner 只有向下越界（负下标）才能到达，role 只有跳过 canary 的写入才能到达。
+type checkedFrame struct {
+	owner  uint64
+	buf    [frame.BufSize]byte
+	canary uint64
+	role   uint64
+}
+
+// checksumCase 是一次写入：off 相对 buf[0]，sealAfter 为 true 时在写入之后才 Seal。
+type checksumCase struct {
+	name      string
+	off       int
+	n         int
+	sealAfter bool
+}
+
+var checksumCases = []checksumCase{
+	{name: "fits in buf", off: 0, n: frame.BufSize},
+	{name: "contiguous overflow (main.go)", off: 0, n: 24},
+	{name: "indexed write past the canary", off: 24, n: 8},
+	{name: "negative index", off: -8, n: 8},
+	{name: "overflow before Seal", off: 0, n: 24, sealAfter: true},
+}
+
+func runChecksum(w io.Writer) (Result, error) {
+	bufOff := int(unsafe.Offsetof(checkedFrame{}.buf))
+	fmt.Fprintf(w, "layout: owner [%d,%d) | buf [%d,%d) | canary [%d,%d) | role [%d,%d)\n",
+		unsafe.Offsetof(checkedFrame{}.owner), bufOff,
+		bufOff, unsafe.Offsetof(checkedFrame{}.canary),
+		unsafe.Offsetof(checkedFrame{}.canary), unsafe.Offsetof(checkedFrame{}.role),
+		unsafe.Offsetof(checkedFrame{}.role), unsafe.Sizeof(checkedFrame{}))
+	fmt.Fprintln(w, "the region seals owner, canary and role (buf is meant to change)")
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CASE\tWRITE\tCANARY CHECK\tCHECKSUM REGION")
+	var canaryHits, regionHits, corrupted int
+	for _, c := range checksumCases {
+		f := checkedFrame{owner: 1000, canary: frame.DefaultCanary, role: 1}
+		r := guard.NewRegion()
+		guard.AddField(r, "owner", &f.owner)
+		guard.AddField(r, "canary", &f.canary)
+		guard.AddField(r, "role", &f.role)
+		if !c.sealAfter {
+			r.Seal()
+		}
+
+		p := make([]byte, c.n)
+		for i := range p {
+			p[i] = 'A'
+		}
+		if _, err := frame.Overwrite(unsafe.Pointer(&f), unsafe.Sizeof(f), int64(bufOff+c.off), p); err != nil {
+			return Result{}, err
+		}
+		if c.sealAfter {
+			r.Seal()
+		}
+		// 只有在其他字段确实被改写时，检查没有报告才算漏报。
+		missed := ""
+		if f.owner != 1000 || f.canary != frame.DefaultCanary || f.role != 1 {
+			corrupted++
+			missed = " (missed)"
+		}
+
+		canary := "intact" + missed
+		if f.canary != frame.DefaultCanary {
+			canary = "fired"
+			canaryHits++
+		}
+		region := "verified" + missed
+		err := r.Verify()
+		var me *guard.ModifiedError
+		switch {
+		case errors.As(err, &me):
+			region = "fired: "
+			for i, fc := range me.Fields {
+				if i > 0 {
+					region += ", "
+				}
+				region += fc.Name
+			}
+			regionHits++
+		case err != nil:
+			return Result{}, err
+		}
+		fmt.Fprintf(tw, "%s\tbuf[%d:%d]\t%s\t%s\n", c.name, c.off, c.off+c.n, canary, region)
+	}
+	if err := tw.Flush(); err != nil {
+		return Result{}, err
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "the canary only sees writes that pass through it; the region sees any sealed field,")
+	fmt.Fprintln(w, "but only between Seal and Verify, and accepts whatever was there when Seal ran")
+
+	return Result{
+		Corrupted: corrupted > 0,
+		Summary: fmt.Sprintf("%d of %d writes corrupted other fields: canary caught %d, checksum region caught %d",
+			corrupted, len(checksumCases), canaryHits, regionHits),
+	}, nil
+}
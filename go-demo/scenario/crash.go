@@ -0,0 +1,55 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"shijian/guard"
+)
+
+func init() {
+	Register(Scenario{
+		Name:    "readonly",
+		Title:   "write into a string literal (read-only data in the binary)",
+		Run:     runReadOnly,
+		Crashes: true,
+	})
+	Register(Scenario{
+		Name:    "unmapped",
+		Title:   "write into a buffer after its pages were unmapped",
+		Run:     runUnmapped,
+		Crashes: true,
+	})
+}
+
+// literal 是一个字符串常量，内容放在可执行文件的只读数据段里。
+const literal = "read-only"
+
+func runReadOnly(w io.Writer) (Result, error) {
+	p := unsafe.StringData(literal)
+	fmt.Fprintf(w, "string literal %q lives at %p, in the binary's read-only data\n", literal, p)
+	fmt.Fprintf(w, "writing 'X' over its first byte through unsafe.StringData ...\n")
+	*p = 'X'
+	fmt.Fprintf(w, "the write succeeded: literal is now %q\n", literal)
+	return Result{Corrupted: true, Summary: "a string constant was modified"}, nil
+}
+
+func runUnmapped(w io.Writer) (Result, error) {
+	b, err := guard.NewPageBuffer(16)
+	if errors.Is(err, guard.ErrPagesUnsupported) {
+		return Result{Summary: "needs Linux (mmap/munmap)"}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	p := (*byte)(b.Pointer())
+	fmt.Fprintf(w, "16-byte buffer at %p; unmapping its pages (like a use-after-free of mmap'd memory)\n", p)
+	if err := b.Close(); err != nil {
+		return Result{}, err
+	}
+	fmt.Fprintf(w, "writing one byte through the stale pointer ...\n")
+	*p = 'X'
+	return Result{Corrupted: true, Summary: "the write to unmapped memory did not fault"}, nil
+}
@@ -0,0 +1,29 @@
+package scenario
+
+import (
+	"fmt"
+	"io"
+
+	"shijian/frame"
+)
+
+func init() {
+	Register(Scenario{
+		Name:  "frame",
+		Title: "the original demo: 24 bytes into frame{buf [16]byte; canary uint64}",
+		Run:   runFrame,
+	})
+}
+
+func runFrame(w io.Writer) (Result, error) {
+	f := frame.New(frame.DefaultCanary)
+	fmt.Fprintf(w, "before: canary = 0x%016x\n", f.Canary())
+	if _, err := f.WriteAt(frame.DemoPayloadOrder(frame.HostByteOrder(), frame.DefaultOverwrite), 0); err != nil {
+		return Result{}, err
+	}
+	fmt.Fprintf(w, "after : canary = 0x%016x\n", f.Canary())
+	if f.CanaryIntact() {
+		return Result{Summary: "canary unchanged"}, nil
+	}
+	return Result{Corrupted: true, Summary: "buf overflow overwrote the adjacent canary"}, nil
+}
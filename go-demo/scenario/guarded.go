@@ -0,0 +1,49 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"shijian/frame"
+	"shijian/guard"
+)
+
+func init() {
+	Register(Scenario{
+		Name:  "guarded",
+		Title: "guard.GuardedBuffer: random sentinels on both sides, checked on Close",
+		Run:   runGuarded,
+	})
+}
+
+func runGuarded(w io.Writer) (Result, error) {
+	b, err := guard.NewWithOptions(frame.BufSize, guard.Options{CheckOnClose: true})
+	if err != nil {
+		return Result{}, err
+	}
+	fmt.Fprintf(w, "buffer: %d data bytes between two %d-byte sentinels from crypto/rand\n", b.Len(), b.SentinelSize())
+
+	// 和演示相同的写法：从 data[0] 开始写 20 字节，多出的 4 字节落进尾部哨兵。
+	payload := []byte("AAAAAAAAAAAAAAAAZZZZ")
+	if _, err := frame.Overwrite(b.Pointer(), uintptr(b.Len()+b.SentinelSize()), 0, payload); err != nil {
+		return Result{}, err
+	}
+	fmt.Fprintf(w, "wrote %d bytes from data[0] with the unsafe write loop\n", len(payload))
+	// 再往 data[0] 之前写 2 字节，模拟下标算错成负数。
+	if _, err := frame.Overwrite(unsafe.Add(b.Pointer(), -2), 2, 0, []byte("<<")); err != nil {
+		return Result{}, err
+	}
+	fmt.Fprintf(w, "wrote 2 bytes just before data[0]\n")
+
+	err = b.Close()
+	fmt.Fprintf(w, "Close: %v\n", err)
+	if errors.Is(err, guard.ErrCorrupted) {
+		return Result{Corrupted: true, Summary: "Close reported both corrupted sentinels and their offsets"}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Summary: "sentinels intact"}, nil
+}
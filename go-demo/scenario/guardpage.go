@@ -0,0 +1,77 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"shijian/frame"
+	"shijian/guard"
+)
+
+func init() {
+	Register(Scenario{
+		Name:  "guardpage",
+		Title: "side by side: plain struct (silent corruption) vs buffer ending at a PROT_NONE page (immediate fault)",
+		Run:   runGuardPage,
+	})
+}
+
+func runGuardPage(w io.Writer) (Result, error) {
+	payload := frame.DemoPayloadOrder(frame.HostByteOrder(), frame.DefaultOverwrite)
+
+	// 左边：原演示，16 字节 buf 后面是 canary。
+	f := frame.New(frame.DefaultCanary)
+	plainN, err := f.WriteAt(payload, 0)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// 右边：同样 16 字节，但末尾紧贴保护页。
+	b, err := guard.NewPageBuffer(frame.BufSize)
+	if errors.Is(err, guard.ErrPagesUnsupported) {
+		fmt.Fprintf(w, "%v; only the plain struct can be shown here\n", err)
+		return Result{Corrupted: !f.CanaryIntact(), Summary: "guard pages unavailable on this platform"}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	defer b.Close()
+	var pageN int
+	fault := guard.CatchFault(func() {
+		pageN, _ = frame.Overwrite(b.Pointer(), uintptr(b.Len()+8), 0, payload)
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "\tplain struct\tguard page\n")
+	fmt.Fprintf(tw, "buffer\tbuf [16]byte, then canary\t16 bytes ending at %#x (PROT_NONE)\n", b.GuardAddr())
+	fmt.Fprintf(tw, "bytes requested\t%d\t%d\n", len(payload), len(payload))
+	switch {
+	case fault != nil && !fault.HasAddr:
+		fmt.Fprintf(tw, "bytes written\t%d\tunknown\n", plainN)
+		fmt.Fprintf(tw, "outcome\tsilent corruption\trecovered panic without a fault address: %s\n", fault.Message)
+	case fault != nil:
+		fmt.Fprintf(tw, "bytes written\t%d\t%d\n", plainN, b.Offset(fault.Addr))
+		fmt.Fprintf(tw, "stopped at\t-\toffset %d (address %#x)\n", b.Offset(fault.Addr), fault.Addr)
+		fmt.Fprintf(tw, "neighbour\tcanary 0x%016x -> 0x%016x\tnever reached\n", frame.DefaultCanary, f.Canary())
+		fmt.Fprintf(tw, "outcome\tsilent corruption\trecovered panic: %s\n", fault.Message)
+	default:
+		fmt.Fprintf(tw, "bytes written\t%d\t%d\n", plainN, pageN)
+		fmt.Fprintf(tw, "outcome\tsilent corruption\tno fault (unexpected)\n")
+	}
+	if err := tw.Flush(); err != nil {
+		return Result{}, err
+	}
+
+	if fault == nil {
+		return Result{Corrupted: true, Summary: "the write past the guard page did not fault"}, nil
+	}
+	if !fault.HasAddr {
+		return Result{Corrupted: !f.CanaryIntact(), Summary: "plain struct: canary silently overwritten; guard page: fault caught, but the runtime gave no address"}, nil
+	}
+	return Result{
+		Corrupted: !f.CanaryIntact(),
+		Summary:   fmt.Sprintf("plain struct: canary silently overwritten; guard page: fault at byte %d, caught by SetPanicOnFault", b.Offset(fault.Addr)),
+	}, nil
+}
@@ -0,0 +1,92 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"shijian/checked"
+	"shijian/frame"
+)
+
+func init() {
+	Register(Scenario{
+		Name:  "intwrap",
+		Title: "uint8 length wraps, the undersized buffer is overflowed; fixed with checked.MulChecked/AddChecked",
+		Run:   runIntWrap,
+	})
+}
+
+// 协议报文：4 字节头部 + count 条记录，每条 recSize 字节。count 与 recSize 来自报文本身。
+const (
+	wrapHeader  = 4
+	wrapCount   = 13
+	wrapRecSize = 20
+)
+
+// wrapArena 模拟分配器的一块内存：前 n 字节交给这次的缓冲区，紧接着是下一块的 8 字节头部，
+// 充当 canary。arena 足够大，真实长度的写入也不会写出这个对象。
+type wrapArena struct {
+	mem [wrapHeader + wrapCount*wrapRecSize + 8]byte
+}
+
+// wrapPacket 返回报文的全部字节：头部为 'H'，第 i 条记录为 'a'+i。
+func wrapPacket() []byte {
+	p := bytes.Repeat([]byte{'H'}, wrapHeader)
+	for i := 0; i < wrapCount; i++ {
+		p = append(p, bytes.Repeat([]byte{'a' + byte(i)}, wrapRecSize)...)
+	}
+	return p
+}
+
+// packetLenUnchecked 是原来的写法：长度在 uint8 里计算，超过 255 时悄悄回绕。
+func packetLenUnchecked(count, recSize uint8) uint8 {
+	return count*recSize + wrapHeader
+}
+
+// packetLenChecked 是修正后的写法：任何一步溢出都返回错误，调用方拒绝这个报文。
+func packetLenChecked(count, recSize uint8) (uint8, error) {
+	body, err := checked.MulChecked(count, recSize)
+	if err != nil {
+		return 0, err
+	}
+	return checked.AddChecked(body, wrapHeader)
+}
+
+func runIntWrap(w io.Writer) (Result, error) {
+	pkt := wrapPacket()
+	var count, recSize uint8 = wrapCount, wrapRecSize
+	fmt.Fprintf(w, "packet: %d records x %d bytes + %d-byte header = %d bytes\n", count, recSize, wrapHeader, len(pkt))
+
+	// 原来的写法：按回绕后的长度“分配”，再按报文真实长度写入。
+	n := int(packetLenUnchecked(count, recSize))
+	fmt.Fprintf(w, "\nunchecked: uint8(%d*%d) = %d, + %d = %d -> %d-byte buffer, next chunk header at [%d,%d)\n",
+		count, recSize, count*recSize, wrapHeader, n, n, n, n+8)
+	var a wrapArena
+	binary.LittleEndian.PutUint64(a.mem[n:], frame.DefaultCanary)
+	// 和 main.go 一样的 unsafe 写循环，长度来自报文而不是缓冲区。
+	if _, err := frame.Overwrite(unsafe.Pointer(&a), unsafe.Sizeof(a), 0, pkt); err != nil {
+		return Result{}, err
+	}
+	hdr := binary.LittleEndian.Uint64(a.mem[n:])
+	fmt.Fprintf(w, "  wrote %d bytes into the %d-byte buffer: %d bytes past its end\n", len(pkt), n, len(pkt)-n)
+	fmt.Fprintf(w, "  next chunk header: 0x%016x -> 0x%016x (%q)\n", frame.DefaultCanary, hdr, a.mem[n:n+8])
+
+	// 修正后的写法：长度计算溢出时拒绝报文，什么也不写。
+	fmt.Fprintln(w, "\nchecked:")
+	if m, err := packetLenChecked(count, recSize); err != nil {
+		fmt.Fprintf(w, "  packet rejected: %v\n", err)
+	} else {
+		fmt.Fprintf(w, "  length %d, buffer allocated with the real size\n", m)
+	}
+	// 边界情况：12 条记录正好 12*20+4 = 244 字节，没有回绕，两种写法得到同一个长度。
+	m, err := packetLenChecked(12, recSize)
+	fmt.Fprintf(w, "  12 records: length %d (unchecked: %d), err %v\n", m, packetLenUnchecked(12, recSize), err)
+
+	if hdr != frame.DefaultCanary {
+		return Result{Corrupted: true, Summary: fmt.Sprintf("uint8 length wrapped to %d and the write ran %d bytes past the buffer; MulChecked rejects the packet", n, len(pkt)-n)}, nil
+	}
+	return Result{Summary: "next chunk header intact"}, nil
+}
@@ -0,0 +1,157 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"shijian/frame"
+)
+
+// offByOne 是一个只越界 1 字节的写法：write 在 frame 上执行有缺陷的循环。
+type offByOne struct {
+	name, title string
+	code        string // 有缺陷的那一行，打印出来便于对照
+	write       func(f *frame.Frame) error
+}
+
+var offByOnes = []offByOne{
+	{
+		name:  "obo-le",
+		title: "off-by-one: <= instead of < in the loop condition",
+		code:  "for i := 0; i <= len(buf); i++ { buf[i] = 'A' }",
+		write: func(f *frame.Frame) error {
+			for i := 0; i <= frame.BufSize; i++ {
+				if _, err := f.WriteAt([]byte{'A'}, int64(i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "obo-terminator",
+		title: "off-by-one: buffer sized by len(s), forgetting the NUL terminator",
+		code:  "n := len(s) /* + 1 forgotten */; copy(buf[:n], s); buf[len(s)] = 0",
+		write: func(f *frame.Frame) error {
+			s := "0123456789abcdef" // 正好 16 个字符，buf 只够放字符，放不下结尾的 NUL
+			if _, err := f.WriteAt([]byte(s), 0); err != nil {
+				return err
+			}
+			_, err := f.WriteAt([]byte{0}, int64(len(s)))
+			return err
+		},
+	},
+	{
+		name:  "obo-reverse",
+		title: "off-by-one: reversed loop starting at len(buf) instead of len(buf)-1",
+		code:  "for i := len(buf); i >= 0; i-- { buf[i] = 'Z' }",
+		write: func(f *frame.Frame) error {
+			for i := frame.BufSize; i >= 0; i-- {
+				if _, err := f.WriteAt([]byte{'Z'}, int64(i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
+
+func init() {
+	for _, o := range offByOnes {
+		Register(Scenario{
+			Name:  o.name,
+			Title: o.title,
+			Run:   o.run,
+		})
+	}
+}
+
+// canaryCheck 是返回前对 canary 的一种检查：比较 [from, to) 范围内的字节（按内存顺序）。
+type canaryCheck struct {
+	name     string
+	from, to int
+}
+
+var canaryChecks = []canaryCheck{
+	{"full 8-byte compare", 0, 8},
+	{"partial: upper half only (canary[4:8])", 4, 8},
+	{"partial: first byte only (canary[0])", 0, 1},
+}
+
+func (o offByOne) run(w io.Writer) (Result, error) {
+	f := frame.New(frame.DefaultCanary)
+	before := f.Snapshot().Raw
+	fmt.Fprintf(w, "code  : %s\n", o.code)
+	// 通过 WriteHook 数实际的写入：写进去的值恰好等于原值时，字节没有变化，但写入确实发生了。
+	past := 0
+	prev := frame.WriteHook
+	frame.WriteHook = func(off int64) {
+		if off >= frame.BufSize {
+			past++
+		}
+		if prev != nil {
+			prev(off)
+		}
+	}
+	err := o.write(f)
+	frame.WriteHook = prev
+	if err != nil {
+		return Result{}, err
+	}
+	after := f.Snapshot().Raw
+
+	changed := 0
+	for i := frame.BufSize; i < len(after); i++ {
+		if after[i] != before[i] {
+			changed++
+		}
+	}
+	fmt.Fprintf(w, "wrote : %d byte(s) past buf, %d of them changed\n", past, changed)
+
+	lo, hi := frame.BufSize, frame.BufSize+8
+	oldC, newC := before[lo:hi], after[lo:hi]
+	var hit []string
+	for i := range newC {
+		if oldC[i] != newC[i] {
+			hit = append(hit, fmt.Sprintf("canary[%d]", i))
+			fmt.Fprintf(w, "canary: canary[%d] (offset %d) 0x%02x -> 0x%02x, %s\n", i, lo+i, oldC[i], newC[i], byteSignificance(i))
+		}
+	}
+	if bytes.Equal(oldC, newC) {
+		fmt.Fprintln(w, "canary: unchanged (the byte written equals the canary byte)")
+	}
+
+	var missed []string
+	for _, c := range canaryChecks {
+		verdict := "notices"
+		if bytes.Equal(oldC[c.from:c.to], newC[c.from:c.to]) {
+			verdict = "misses"
+			missed = append(missed, c.name)
+		}
+		fmt.Fprintf(w, "check : %-40s %s\n", c.name, verdict)
+	}
+
+	if f.CanaryIntact() {
+		return Result{Summary: "canary unchanged"}, nil
+	}
+	summary := fmt.Sprintf("wrote %d byte(s) past buf, changing %s; the full compare notices, %d of %d partial checks miss",
+		past, strings.Join(hit, ", "), len(missed), len(canaryChecks)-1)
+	return Result{Corrupted: true, Summary: summary}, nil
+}
+
+// byteSignificance 说明 canary 的第 i 个字节（按内存顺序）在宿主字节序下是整数的哪一字节。
+func byteSignificance(i int) string {
+	if frame.HostByteOrder() == binary.BigEndian {
+		i = 7 - i
+	}
+	switch i {
+	case 0:
+		return "the least significant byte of the uint64 on this host"
+	case 7:
+		return "the most significant byte of the uint64 on this host"
+	}
+	return fmt.Sprintf("byte %d of the uint64 (counting from the least significant) on this host", i)
+}
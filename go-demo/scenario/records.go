@@ -0,0 +1,100 @@
+package scenario
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"shijian/frame"
+	"shijian/typelayout"
+)
+
+// 表驱动代码里常见的“记录数组”：buf 夹在其他字段之间。
+
+// packedRec 没有任何填充：buf 之后紧跟 crc，再之后就是下一条记录的 id。
+type packedRec struct {
+	id  uint32
+	buf [12]byte
+	crc uint32
+}
+
+// paddedRec 的 buf 之后有对齐填充，记录末尾也有填充，越界写要先“穿过”它们。
+type paddedRec struct {
+	id   uint32
+	buf  [10]byte
+	seq  uint64
+	kind byte
+}
+
+func init() {
+	Register(Scenario{
+		Name:  "records",
+		Title: "array of records: overflow from rows[i].buf into rows[i+1]",
+		Run:   runRecords,
+	})
+}
+
+func runRecords(w io.Writer) (Result, error) {
+	var res Result
+	var hits []string
+	for _, t := range []reflect.Type{reflect.TypeOf(packedRec{}), reflect.TypeOf(paddedRec{})} {
+		for _, overshoot := range []int{8, 24} {
+			next, err := overflowRecords(w, t, overshoot)
+			if err != nil {
+				return res, err
+			}
+			if len(next) > 0 {
+				res.Corrupted = true
+				hits = append(hits, fmt.Sprintf("%s+%d → %s", t.Name(), overshoot, strings.Join(next, ",")))
+			}
+		}
+	}
+	res.Summary = "next record overwritten in: " + strings.Join(hits, "; ")
+	return res, nil
+}
+
+// overflowRecords 在 [3]t 上从 Rows[0].buf 开始写入 len(buf)+overshoot 个字节，
+// 打印逐字段的归类摘要，并返回 Rows[1] 中被改变的字段。
+func overflowRecords(w io.Writer, t reflect.Type, overshoot int) ([]string, error) {
+	rows := reflect.ArrayOf(3, t)
+	r, err := typelayout.Of(reflect.StructOf([]reflect.StructField{{Name: "Rows", Type: rows}}))
+	if err != nil {
+		return nil, err
+	}
+	bufField, _ := t.FieldByName("buf")
+	start := bufField.Offset
+	n := int(bufField.Type.Size()) + overshoot
+
+	// 每条记录填入不同的字节（Rows[0] 全 0x11，Rows[1] 全 0x22……），写入内容为 'A'。
+	v := reflect.New(rows)
+	obj := unsafe.Slice((*byte)(v.UnsafePointer()), rows.Size())
+	for i := range obj {
+		obj[i] = byte(0x11 * (1 + uintptr(i)/t.Size()))
+	}
+	before := append([]byte(nil), obj...)
+	payload := make([]byte, n)
+	for i := range payload {
+		payload[i] = 'A'
+	}
+	frame.Overwrite(v.UnsafePointer(), rows.Size(), int64(start), payload)
+	m := r.Map(start, n, before, obj)
+
+	fmt.Fprintf(w, "%s (size %d, align %d): write len(buf)+%d = %d bytes from Rows[0].buf\n", t, t.Size(), t.Align(), overshoot, n)
+	var next []string
+	for _, s := range m.Summary() {
+		fmt.Fprintf(w, "  %s\n", s)
+	}
+	for _, f := range m.Fields {
+		if f.Changed > 0 && strings.HasPrefix(f.Path, "Rows[1].") {
+			next = append(next, strings.TrimPrefix(f.Path, "Rows[1]."))
+		}
+	}
+	if len(next) == 0 {
+		fmt.Fprintf(w, "  => Rows[1] untouched: the overflow stayed inside Rows[0] (fields and padding)\n\n")
+	} else {
+		fmt.Fprintf(w, "  => Rows[1] fields overwritten: %s\n\n", strings.Join(next, ", "))
+	}
+	return next, nil
+}
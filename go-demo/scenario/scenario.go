@@ -0,0 +1,59 @@
+// Package scenario 收集可以按名字运行的越界写教学场景（shijian scenario <name>）。
+// 每个场景在自己的文件里通过 init 调用 Register 注册。
+package scenario
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Result 是场景运行结束后的结论。
+type Result struct {
+	// Corrupted 表示有场景本不该修改的数据被改写。
+	Corrupted bool
+	// Summary 是一句话结论。
+	Summary string
+}
+
+// Scenario 是一个可运行的教学场景。Run 把过程写到 w 并返回结论。
+type Scenario struct {
+	Name  string
+	Title string
+	Run   func(w io.Writer) (Result, error)
+	// Crashes 表示场景会让进程崩溃（写只读或未映射的内存），应通过 harness 运行。
+	Crashes bool
+}
+
+var registry = map[string]Scenario{}
+
+// Register 注册一个场景；名字重复时 panic。
+func Register(s Scenario) {
+	if _, dup := registry[s.Name]; dup {
+		panic("scenario: duplicate name " + s.Name)
+	}
+	registry[s.Name] = s
+}
+
+// All 按名字排序返回全部场景。
+func All() []Scenario {
+	out := make([]Scenario, 0, len(registry))
+	for _, s := range registry {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Lookup 按名字查找场景。
+func Lookup(name string) (Scenario, error) {
+	if s, ok := registry[name]; ok {
+		return s, nil
+	}
+	names := make([]string, 0, len(registry))
+	for _, s := range All() {
+		names = append(names, s.Name)
+	}
+	return Scenario{}, fmt.Errorf("unknown scenario %q (known: %s)", name, strings.Join(names, ", "))
+}
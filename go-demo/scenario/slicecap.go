@@ -0,0 +1,99 @@
+package scenario
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"unsafe"
+
+	"shijian/frame"
+)
+
+func init() {
+	Register(Scenario{
+		Name:  "slicecap",
+		Title: "slice len vs cap vs the real allocation (size-class rounding and the heap neighbour)",
+		Run:   runSliceCap,
+	})
+}
+
+// usableSize 返回申请 n 字节时运行时实际分配的大小（按 size class 向上取整）。
+// 借助 append 的扩容路径：从 nil 追加 n 字节时，新容量正是 roundupsize(n)。
+func usableSize(n int) int {
+	return cap(append([]byte(nil), make([]byte, n)...))
+}
+
+func runSliceCap(w io.Writer) (Result, error) {
+	var res Result
+
+	// 1. 超过 len 但仍在 cap 之内：内存属于同一个切片，只是“暂时不可见”。
+	s := make([]byte, 10, 16)
+	fmt.Fprintf(w, "1) s := make([]byte, 10, 16): len=%d cap=%d\n", len(s), cap(s))
+	for i := len(s); i < cap(s); i++ {
+		frame.TraceWrite(int64(i))
+		*(*byte)(unsafe.Add(unsafe.Pointer(unsafe.SliceData(s)), i)) = 'X'
+	}
+	fmt.Fprintf(w, "   wrote 'X' to s[10:16] through unsafe.Add: s still reads %q\n", s)
+	fmt.Fprintf(w, "   s = s[:cap(s)] makes them visible: %q\n", s[:cap(s)])
+	fmt.Fprintf(w, "   => within cap the bytes belong to s; a later append(s, ...) reuses (and overwrites) them\n\n")
+
+	// 2. 超过 cap：size class 向上取整后的“尾部空隙”仍属于同一个分配。
+	const n = 20
+	usable := usableSize(n)
+	fmt.Fprintf(w, "2) make([]byte, %d): cap=%d, but the runtime rounds the allocation up to its size class: usable=%d (%d slack bytes)\n", n, n, usable, usable-n)
+
+	// 3. 再往后就是下一个对象。连续分配同一 size class 的对象，找一对地址正好相邻的。
+	a, b := adjacentPair(n, usable)
+	if a == nil {
+		fmt.Fprintf(w, "3) no pair of adjacent %d-byte objects found in this run; not writing past the allocation\n", usable)
+		res.Summary = "no heap neighbour found"
+		return res, nil
+	}
+	for i := range b {
+		b[i] = 'B'
+	}
+	before := append([]byte(nil), b...)
+	fmt.Fprintf(w, "3) a=%p (len %d), neighbour b=%p = a+%d\n", unsafe.SliceData(a), len(a), unsafe.SliceData(b), usable)
+	fmt.Fprintf(w, "   b before: % x\n", b)
+
+	// unsafe.Slice 把 a 的视图扩展到整个分配（含尾部空隙），再用 unsafe.Add 越过分配边界。
+	whole := unsafe.Slice(unsafe.SliceData(a), usable)
+	for i := n; i < usable; i++ {
+		whole[i] = 'S'
+	}
+	for i := usable; i < usable+4; i++ {
+		frame.TraceWrite(int64(i))
+		*(*byte)(unsafe.Add(unsafe.Pointer(unsafe.SliceData(a)), i)) = 'Z'
+	}
+	fmt.Fprintf(w, "   wrote %d 'S' into a's slack (a[%d:%d] via unsafe.Slice) and 4 'Z' past the allocation (unsafe.Add)\n", usable-n, n, usable)
+	fmt.Fprintf(w, "   b after : % x\n", b)
+	runtime.KeepAlive(a)
+
+	if !bytes.Equal(before, b) {
+		res.Corrupted = true
+		fmt.Fprintf(w, "   => the slack absorbed %d bytes silently; the next 4 landed in a different object\n", usable-n)
+		res.Summary = fmt.Sprintf("write past a's %d-byte size class corrupted the heap neighbour b", usable)
+	} else {
+		res.Summary = "neighbour unchanged"
+	}
+	return res, nil
+}
+
+// adjacentPair 分配一批 n 字节的对象，返回一对 b 紧跟在 a 之后（相距 usable）的对象。
+func adjacentPair(n, usable int) (a, b []byte) {
+	objs := make([][]byte, 64)
+	for i := range objs {
+		objs[i] = make([]byte, n)
+	}
+	byAddr := make(map[uintptr][]byte, len(objs))
+	for _, o := range objs {
+		byAddr[uintptr(unsafe.Pointer(unsafe.SliceData(o)))] = o
+	}
+	for addr, o := range byAddr {
+		if next, ok := byAddr[addr+uintptr(usable)]; ok {
+			return o, next
+		}
+	}
+	return nil, nil
+}
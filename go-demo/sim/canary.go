@@ -0,0 +1,154 @@
+package sim
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// canary 策略名。
+const (
+	CanaryFixed         = "fixed"           // 编译进程序的常量（main.go）
+	CanaryRandom        = "random"          // 每个进程随机一次（app.js 的 createFrame）
+	CanaryTerminator    = "terminator"      // 固定的 NUL / CR / LF / 0xFF 字节
+	CanaryRandomXORAddr = "random-xor-addr" // 每个进程的随机值 XOR 栈帧地址
+)
+
+// CanaryStrategy 是一种生成 canary 的方式。
+type CanaryStrategy struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// CanaryStrategies 按展示顺序列出所有策略。
+var CanaryStrategies = []CanaryStrategy{
+	{CanaryFixed, "compile-time constant (main.go): the same in every run of the program"},
+	{CanaryRandom, "random once per process (app.js createFrame): the same in every frame"},
+	{CanaryTerminator, "fixed bytes 00 0d 0a ff: string copies stop at them or cannot write them back"},
+	{CanaryRandomXORAddr, "per-process random XOR the frame address: different in every frame"},
+}
+
+// FixedCanary 与 frame.DefaultCanary 相同。
+const FixedCanary uint64 = 0x1122334455667788
+
+// TerminatorCanary 按内存顺序包含 NUL、CR、LF、0xFF：NUL 在最低地址，strcpy 在这里停下。
+var TerminatorCanary = [SizeCanary]byte{0x00, 0x0d, 0x0a, 0xff, 0x00, 0x0d, 0x0a, 0xff}
+
+// 概念上的栈帧地址：FrameAddr 是被写入的帧，OtherFrameAddr 是同一进程里的另一个帧。
+const (
+	FrameAddr      uint64 = 0x00007ffff0f0f0b0
+	OtherFrameAddr uint64 = 0x00007ffff0f0f030
+)
+
+// CanaryValue 返回策略在地址为 frameAddr 的帧里放置的 canary；secret 是进程启动时的随机值。
+func CanaryValue(strategy string, secret, frameAddr uint64) ([SizeCanary]byte, error) {
+	var c [SizeCanary]byte
+	switch strategy {
+	case CanaryFixed:
+		binary.LittleEndian.PutUint64(c[:], FixedCanary)
+	case CanaryRandom:
+		binary.LittleEndian.PutUint64(c[:], secret)
+	case CanaryTerminator:
+		c = TerminatorCanary
+	case CanaryRandomXORAddr:
+		binary.LittleEndian.PutUint64(c[:], secret^frameAddr)
+	default:
+		return c, fmt.Errorf("sim: unknown canary strategy %q", strategy)
+	}
+	return c, nil
+}
+
+// KnownCanary 返回不读取这个帧也能得到的 canary 值：常量策略就是常量本身，
+// 随机策略是同一进程另一个帧（OtherFrameAddr）里的值。
+func KnownCanary(strategy string, secret uint64) ([SizeCanary]byte, error) {
+	return CanaryValue(strategy, secret, OtherFrameAddr)
+}
+
+// PatternKnownValue 是矩阵里额外的一列：写入内容在 canary 的位置恰好是 KnownCanary 的值。
+const PatternKnownValue = "known-value"
+
+// KnownValuePayload 返回 n 字节的写入内容：buf 部分为 'A'，canary 位置为 known，之后为 'B'。
+func KnownValuePayload(known [SizeCanary]byte, n int) []byte {
+	p := make([]byte, n)
+	for i := range p {
+		switch {
+		case i < OffCanary:
+			p[i] = 'A'
+		case i < OffRBP:
+			p[i] = known[i-OffCanary]
+		default:
+			p[i] = 'B'
+		}
+	}
+	return p
+}
+
+// canary 检查的结果。
+const (
+	OutcomeFires     = "fires"     // canary 被改写，返回前的检查会终止程序
+	OutcomeBypassed  = "bypassed"  // canary 完好，但它之后的 saved RBP / return address 被改写
+	OutcomeContained = "contained" // canary 之后什么也没变
+)
+
+// Payload 是一种具名的写入内容（复制前的源数据）。
+type Payload struct {
+	Name  string
+	Bytes []byte
+}
+
+// CanaryTrial 是矩阵中的一格：某个策略、复制语义与写入内容的组合。
+type CanaryTrial struct {
+	Strategy string   `json:"strategy"`
+	Copy     string   `json:"copy"`
+	Pattern  string   `json:"pattern"`
+	Written  int      `json:"written"` // 复制实际写入帧内的字节数
+	Outcome  string   `json:"outcome"`
+	Changed  []string `json:"changed"`
+}
+
+// RunCanaryTrial 在默认布局上用 strategy 生成 canary，把 src 按 op 复制进 buf，判断 canary 检查是否触发。
+func RunCanaryTrial(strategy, op string, p Payload, secret uint64) (CanaryTrial, error) {
+	t := CanaryTrial{Strategy: strategy, Copy: op, Pattern: p.Name}
+	c, err := CanaryValue(strategy, secret, FrameAddr)
+	if err != nil {
+		return t, err
+	}
+	f := NewFrame(c)
+	r, err := f.Copy(op, p.Bytes, DefaultMitigations)
+	if err != nil {
+		return t, err
+	}
+	t.Written, t.Changed = r.Written, r.Changed
+	s := f.ComputeStatus(DefaultMitigations)
+	switch {
+	case s.CanaryChanged:
+		t.Outcome = OutcomeFires
+	case s.RBPChanged || s.RetChanged:
+		t.Outcome = OutcomeBypassed
+	default:
+		t.Outcome = OutcomeContained
+	}
+	return t, nil
+}
+
+// CanaryMatrix 对每个策略、每种复制语义运行 payloads 中的每个写入内容，
+// 再加上一列 PatternKnownValue（长度与 n 相同）。结果按策略、复制语义、写入内容的顺序排列。
+func CanaryMatrix(payloads []Payload, n int, secret uint64) ([]CanaryTrial, error) {
+	var out []CanaryTrial
+	for _, s := range CanaryStrategies {
+		known, err := KnownCanary(s.Name, secret)
+		if err != nil {
+			return nil, err
+		}
+		ps := append(payloads[:len(payloads):len(payloads)], Payload{PatternKnownValue, KnownValuePayload(known, n)})
+		for _, op := range CopyOps {
+			for _, p := range ps {
+				t, err := RunCanaryTrial(s.Name, op, p, secret)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, t)
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,89 @@
+package sim
+
+import "fmt"
+
+// 复制语义：同一段源数据，经过不同的复制函数后实际写进栈帧的字节不同。
+// 源数据按 C 字符串理解时，到第一个 NUL 为止；没有 NUL 时视为紧跟着一个 NUL。
+const (
+	CopyMemcpy  = "memcpy"  // memcpy(dst, src, len(src))：按长度复制，不看内容
+	CopyStrcpy  = "strcpy"  // strcpy(dst, src)：复制到第一个 NUL 为止（包括 NUL）
+	CopyStrncpy = "strncpy" // strncpy(dst, src, sizeof dst)：最多写满 dst，源较短时补 NUL，较长时不写结尾的 NUL
+	CopyGo      = "copy"    // Go 的 copy(dst, src)：写 min(len(dst), len(src)) 个字节
+)
+
+// CopyOps 按展示顺序列出所有复制语义。
+var CopyOps = []string{CopyMemcpy, CopyStrcpy, CopyStrncpy, CopyGo}
+
+// CopyBytes 返回用 op 把 src 复制到长度为 dstLen 的写入目标时，从目标首字节开始实际写入的字节。
+func CopyBytes(op string, src []byte, dstLen int) ([]byte, error) {
+	switch op {
+	case CopyMemcpy:
+		return append([]byte(nil), src...), nil
+	case CopyStrcpy:
+		return append(cString(src), 0), nil
+	case CopyStrncpy:
+		out := make([]byte, dstLen) // 不足部分补 NUL
+		copy(out, cString(src))
+		return out, nil
+	case CopyGo:
+		return append([]byte(nil), src[:min(len(src), dstLen)]...), nil
+	}
+	return nil, fmt.Errorf("sim: unknown copy op %q", op)
+}
+
+// cString 返回 src 中第一个 NUL 之前的部分（不含 NUL）。
+func cString(src []byte) []byte {
+	for i, b := range src {
+		if b == 0 {
+			return src[:i]
+		}
+	}
+	return src
+}
+
+// CopyResult 描述一次复制的效果。
+type CopyResult struct {
+	Op         string   `json:"op"`
+	Written    int      `json:"written"`    // 实际写入帧内的字节数（超出帧末尾、被丢弃的不算）
+	Past       int      `json:"past"`       // 其中越过写入目标末尾的字节数
+	Terminated bool     `json:"terminated"` // 写入目标里是否写进了 NUL
+	Strlen     int      `json:"strlen"`     // 复制后对目标调用 strlen 的结果（读到帧内第一个 NUL）；帧内没有 NUL 时为 -1
+	Changed    []string `json:"changed"`
+	Verdict    Verdict  `json:"verdict"`
+}
+
+// ReadPast 返回之后按字符串读取目标时越过目标末尾的字节数；帧内没有 NUL 时为 -1。
+func (r CopyResult) ReadPast(targetSize int) int {
+	if r.Strlen < 0 {
+		return -1
+	}
+	return max(0, r.Strlen-targetSize)
+}
+
+// Copy 用 op 把 src 复制到布局的写入目标，执行写入并返回效果；结论使用 m。
+// 会替换当前的写入计划。
+func (f *Frame) Copy(op string, src []byte, m Mitigations) (CopyResult, error) {
+	t, _ := f.Layout.Segment(f.Layout.Target)
+	w, err := CopyBytes(op, src, t.Size)
+	if err != nil {
+		return CopyResult{}, err
+	}
+	f.SetPlan(w)
+	f.Run()
+	s := f.ComputeStatus(m)
+	r := CopyResult{Op: op, Written: s.WrittenCount, Past: max(0, s.WrittenCount-t.Size), Strlen: -1}
+	for _, b := range w[:min(len(w), t.Size)] {
+		if b == 0 {
+			r.Terminated = true
+			break
+		}
+	}
+	for i, b := range f.Mem[t.Offset:] {
+		if b == 0 {
+			r.Strlen = i
+			break
+		}
+	}
+	r.Changed, r.Verdict = s.Changed, s.Verdict
+	return r, nil
+}
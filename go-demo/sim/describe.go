@@ -0,0 +1,44 @@
+package sim
+
+import "fmt"
+
+// Describe 对应 describeNow：用一句话描述当前写入进度与结论。
+func (f *Frame) Describe(m Mitigations) string {
+	s := f.ComputeStatus(m)
+	canaryFail := m.Canary && s.CanaryChanged
+
+	if s.PlanLen == 0 {
+		return "写入长度为 0：不会写入任何字节。"
+	}
+	if !s.Started {
+		return fmt.Sprintf("准备从 %s[0] 开始写入，共 %d 字节。先点“单步写 1 字节”试试。", f.Layout.Target, s.PlanLen)
+	}
+	if !s.Finished {
+		l := f.Layout
+		lastText := l.Label(s.LastSeg)
+		nextText := "（完成）"
+		if s.NextSeg != "" {
+			nextText = l.Label(s.NextSeg)
+		}
+		target, _ := l.Segment(l.Target)
+		warn := fmt.Sprintf("还未越界：仍在 %s 内。", l.Label(l.Target))
+		if s.Cursor > target.Size {
+			warn = "已经越界：开始覆盖相邻字段。"
+		}
+		return fmt.Sprintf("刚写入第 %d 个字节（上一次落在 %s）。下一次将写到 %s。%s", s.Cursor, lastText, nextText, warn)
+	}
+
+	switch {
+	case canaryFail:
+		return "写入完成，但 Canary 被改写：返回前检查会失败（模拟），通常在 ret 前终止。"
+	case s.RetChanged:
+		return "写入完成，返回地址已被改写：现实中 ret 时可能跳飞/崩溃（这里用抽象结论表示）。"
+	case s.RBPChanged:
+		return "写入完成，saved RBP 被改写：现实中后续栈帧恢复/变量访问可能异常（模拟）。"
+	case s.CanaryChanged:
+		return "写入完成，canary 被改写：若未启用 Canary 校验，可能不立刻终止，但内存已被破坏（模拟）。"
+	case s.LocalChanged:
+		return "写入完成，相邻局部变量被改写：程序后续读取到的值已不是原来的（模拟）。"
+	}
+	return fmt.Sprintf("写入完成：只写在 %s 内，没有覆盖到相邻字段。", f.Layout.Target)
+}
@@ -0,0 +1,201 @@
+package sim
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+)
+
+// 段的种类（kind），决定改写后给出哪一类结论。
+const (
+	KindLocal         = "local"          // 普通局部变量/缓冲区（默认）
+	KindPadding       = "padding"        // 对齐填充：被改写不影响结论
+	KindFramePointer  = "frame-pointer"  // saved RBP（示意）
+	KindReturnAddress = "return-address" // return address（示意）
+)
+
+// InitRandom 作为 init 值时表示用伪随机字节初始化（如 canary）。
+const InitRandom = "random"
+
+// Segment 描述布局中的一段连续字节。
+type Segment struct {
+	Name      string `json:"name"`
+	Label     string `json:"label,omitempty"` // 显示名；为空时使用 Name
+	Size      int    `json:"size"`
+	Kind      string `json:"kind,omitempty"`
+	Init      string `json:"init,omitempty"`      // 按内存顺序的 hex 字节，或 "random"；为空表示全 0
+	Protected bool   `json:"protected,omitempty"` // 哨兵字段：返回前会被校验（Canary 开关）
+
+	Offset int `json:"-"`
+}
+
+// Layout 是一个可由 JSON 文件描述的栈帧布局，段按低地址到高地址排列。
+type Layout struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Target      string    `json:"target,omitempty"` // 写入起点所在的段；为空时为第一段
+	Segments    []Segment `json:"segments"`
+
+	size int
+}
+
+// DefaultLayout 返回与 app.js 相同的布局：buf(16) → canary(8) → saved RBP(8) → return address(8)。
+func DefaultLayout() *Layout {
+	l := &Layout{
+		Name:   "default",
+		Target: SegBuf,
+		Segments: []Segment{
+			{Name: SegBuf, Label: "buf", Size: SizeBuf},
+			{Name: SegCanary, Label: "canary", Size: SizeCanary, Init: InitRandom, Protected: true},
+			{Name: SegRBP, Label: "saved RBP", Size: SizeRBP, Kind: KindFramePointer, Init: u64Hex(InitialRBP)},
+			{Name: SegRet, Label: "return addr", Size: SizeRet, Kind: KindReturnAddress, Init: u64Hex(InitialRet)},
+		},
+	}
+	if err := l.init(); err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// LoadLayout 从 JSON 文件读取布局。
+func LoadLayout(path string) (*Layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l, err := ParseLayout(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// ParseLayout 解析并校验 JSON 布局。
+func ParseLayout(data []byte) (*Layout, error) {
+	var l Layout
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("sim: parse layout: %w", err)
+	}
+	if err := l.init(); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// init 校验各段并计算偏移。
+func (l *Layout) init() error {
+	if len(l.Segments) == 0 {
+		return errors.New("sim: layout has no segments")
+	}
+	seen := map[string]bool{}
+	off := 0
+	for i := range l.Segments {
+		s := &l.Segments[i]
+		switch {
+		case s.Name == "" || s.Name == SegOOB:
+			return fmt.Errorf("sim: segment %d: invalid name %q", i, s.Name)
+		case seen[s.Name]:
+			return fmt.Errorf("sim: duplicate segment %q", s.Name)
+		case s.Size <= 0:
+			return fmt.Errorf("sim: segment %q: size must be positive", s.Name)
+		}
+		switch s.Kind {
+		case "":
+			s.Kind = KindLocal
+		case KindLocal, KindPadding, KindFramePointer, KindReturnAddress:
+		default:
+			return fmt.Errorf("sim: segment %q: unknown kind %q", s.Name, s.Kind)
+		}
+		if s.Init != "" && s.Init != InitRandom {
+			b, err := hex.DecodeString(s.Init)
+			if err != nil {
+				return fmt.Errorf("sim: segment %q: init: %w", s.Name, err)
+			}
+			if len(b) != s.Size {
+				return fmt.Errorf("sim: segment %q: init has %d bytes, want %d", s.Name, len(b), s.Size)
+			}
+		}
+		seen[s.Name] = true
+		s.Offset = off
+		off += s.Size
+	}
+	if l.Target == "" {
+		l.Target = l.Segments[0].Name
+	} else if !seen[l.Target] {
+		return fmt.Errorf("sim: target segment %q not found", l.Target)
+	}
+	l.size = off
+	return nil
+}
+
+// Size 返回布局的总字节数。
+func (l *Layout) Size() int { return l.size }
+
+// Segment 按名字查找段。
+func (l *Layout) Segment(name string) (Segment, bool) {
+	for _, s := range l.Segments {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Segment{}, false
+}
+
+// TargetOffset 返回写入起点（目标段首字节）的绝对偏移。
+func (l *Layout) TargetOffset() int {
+	s, _ := l.Segment(l.Target)
+	return s.Offset
+}
+
+// SegmentAt 返回绝对下标 i 所在的段名；超出布局时返回 SegOOB。
+func (l *Layout) SegmentAt(i int) string {
+	for _, s := range l.Segments {
+		if i >= s.Offset && i < s.Offset+s.Size {
+			return s.Name
+		}
+	}
+	return SegOOB
+}
+
+// Label 返回段的显示名。
+func (l *Layout) Label(name string) string {
+	if s, ok := l.Segment(name); ok && s.Label != "" {
+		return s.Label
+	}
+	if name == SegOOB {
+		return "（帧外）"
+	}
+	return name
+}
+
+// initial 按布局生成初始内存；r 为 nil 时 "random" 段保持全 0。
+func (l *Layout) initial(r *rand.Rand) []byte {
+	mem := make([]byte, l.size)
+	for _, s := range l.Segments {
+		switch s.Init {
+		case "":
+		case InitRandom:
+			if r == nil {
+				continue
+			}
+			for i := 0; i < s.Size; i++ {
+				mem[s.Offset+i] = byte(r.Intn(256))
+			}
+		default:
+			b, _ := hex.DecodeString(s.Init)
+			copy(mem[s.Offset:], b)
+		}
+	}
+	return mem
+}
+
+func u64Hex(v uint64) string {
+	var b [8]byte
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+	return hex.EncodeToString(b[:])
+}
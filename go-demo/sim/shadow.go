@@ -0,0 +1,177 @@
+package sim
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 影子字节的取值，沿用 AddressSanitizer 的图例。
+const (
+	ShadowAddressable  byte = 0x00 // 局部变量：程序可以合法读写
+	ShadowMidRedzone   byte = 0xf2 // 两个局部变量之间的填充、哨兵等
+	ShadowRightRedzone byte = 0xf3 // 最后一个局部变量之后：canary、saved RBP、return address
+)
+
+// ShadowName 返回影子字节在 ASan 图例中的名称。
+func ShadowName(v byte) string {
+	switch v {
+	case ShadowAddressable:
+		return "addressable"
+	case ShadowMidRedzone:
+		return "stack mid redzone"
+	case ShadowRightRedzone:
+		return "stack right redzone"
+	}
+	return fmt.Sprintf("shadow %02x", v)
+}
+
+// Shadow 是布局的逐字节影子内存：局部变量（kind 为 local 且不是 protected 哨兵）可寻址，
+// 其他字节（填充、canary 等哨兵、saved RBP、return address）都是 redzone。
+//
+// 真实的 ASan 会在每个局部变量之间插入 redzone；模型的布局是固定的，
+// 两个紧挨着的局部变量之间没有字节可以投毒，从写入目标连续写进另一个局部变量不会被发现，
+// 直到写到下一个 redzone 为止。
+type Shadow struct {
+	Layout *Layout
+	Bytes  []byte // 与 Frame.Mem 一一对应
+}
+
+// NewShadow 按布局生成影子内存。
+func NewShadow(l *Layout) *Shadow {
+	lastLocal := 0
+	for _, s := range l.Segments {
+		if addressable(s) {
+			lastLocal = s.Offset + s.Size
+		}
+	}
+	b := make([]byte, l.Size())
+	for _, s := range l.Segments {
+		if addressable(s) {
+			continue
+		}
+		v := ShadowMidRedzone
+		if s.Offset >= lastLocal {
+			v = ShadowRightRedzone
+		}
+		for i := s.Offset; i < s.Offset+s.Size; i++ {
+			b[i] = v
+		}
+	}
+	return &Shadow{Layout: l, Bytes: b}
+}
+
+// addressable 判断段是否是程序可以合法访问的对象。
+func addressable(s Segment) bool { return s.Kind == KindLocal && !s.Protected }
+
+// ShadowReport 是第一次写入被投毒字节时的报告，对应 ASan 的错误报告。
+type ShadowReport struct {
+	Kind          string `json:"kind"`          // ASan 的错误类型
+	Cursor        int    `json:"cursor"`        // 这是写入的第几个字节（0 起）
+	WriteLen      int    `json:"writeLen"`      // 整次写入的长度
+	Index         int    `json:"index"`         // 帧内绝对偏移
+	Value         byte   `json:"value"`         // 本来要写入的值（没有写入）
+	Segment       string `json:"segment"`       // 被写到的段；超出布局时为 SegOOB
+	SegmentOffset int    `json:"segmentOffset"` // 在该段内的偏移
+	Shadow        byte   `json:"shadow"`        // 该字节的影子值；超出布局时为 0
+	Target        string `json:"target"`        // 写入目标（溢出的变量）
+
+	shadow *Shadow
+}
+
+// CheckNext 按影子值检查计划中的下一次写入：落在可寻址字节上时返回 nil，
+// 落在被投毒的字节上或超出帧时返回报告（不执行写入）。计划已写完时也返回 nil。
+func (f *Frame) CheckNext(sh *Shadow) *ShadowReport {
+	if f.cursor >= len(f.plan) {
+		return nil
+	}
+	l := f.Layout
+	idx := l.TargetOffset() + f.cursor
+	if idx < len(sh.Bytes) && sh.Bytes[idx] == ShadowAddressable {
+		return nil
+	}
+	seg := l.SegmentAt(idx)
+	r := &ShadowReport{
+		Kind:     "stack-buffer-overflow",
+		Cursor:   f.cursor,
+		WriteLen: len(f.plan),
+		Index:    idx,
+		Value:    f.plan[f.cursor],
+		Segment:  seg,
+		Target:   l.Target,
+		shadow:   sh,
+	}
+	if s, ok := l.Segment(seg); ok {
+		r.SegmentOffset = idx - s.Offset
+		r.Shadow = sh.Bytes[idx]
+	}
+	return r
+}
+
+// RunShadow 像 Run 一样执行剩余计划，但每次写入前先查影子内存，
+// 在第一次写到影子值不为 00 的字节（或越出帧）时停下并返回报告；
+// 这一次写入不会执行，和 ASan 在访问前检查、发现错误即终止一致。
+// 返回实际写入的字节数；全部写完时报告为 nil。
+func (f *Frame) RunShadow(sh *Shadow) (n int, rep *ShadowReport) {
+	for {
+		if rep := f.CheckNext(sh); rep != nil {
+			return n, rep
+		}
+		if _, _, ok := f.Step(); !ok {
+			return n, nil
+		}
+		n++
+	}
+}
+
+// String 以 ASan 报告的格式输出：错误类型、哪一次写入、帧内对象，以及影子内存。
+func (r *ShadowReport) String() string {
+	var b strings.Builder
+	l := r.shadow.Layout
+	fmt.Fprintf(&b, "ERROR: AddressSanitizer (model): %s at frame offset %d\n", r.Kind, r.Index)
+	fmt.Fprintf(&b, "WRITE of size 1: byte %d of a %d-byte write starting at '%s'[0], value 0x%02x (not written)\n",
+		r.Cursor, r.WriteLen, r.Target, r.Value)
+	if r.Segment == SegOOB {
+		fmt.Fprintf(&b, "  frame offset %d is past the end of the %d-byte frame\n", r.Index, l.Size())
+	} else {
+		fmt.Fprintf(&b, "  frame offset %d is %s+%d (shadow %02x: %s)\n", r.Index, l.Label(r.Segment), r.SegmentOffset, r.Shadow, ShadowName(r.Shadow))
+	}
+
+	var objs []Segment
+	for _, s := range l.Segments {
+		if addressable(s) {
+			objs = append(objs, s)
+		}
+	}
+	fmt.Fprintf(&b, "  This frame has %d object(s):\n", len(objs))
+	for _, s := range objs {
+		note := ""
+		if s.Name == r.Target {
+			note = fmt.Sprintf(" <== Memory access at offset %d overflows this variable", r.Index)
+		}
+		fmt.Fprintf(&b, "    [%d, %d) '%s'%s\n", s.Offset, s.Offset+s.Size, s.Name, note)
+	}
+
+	b.WriteString("Shadow bytes of the frame (one per byte; [..] marks the write):\n")
+	const perRow = 16
+	for row := 0; row < len(r.shadow.Bytes); row += perRow {
+		marker := "  "
+		if r.Index >= row && r.Index < row+perRow {
+			marker = "=>"
+		}
+		fmt.Fprintf(&b, "%s%02x:", marker, row)
+		for i := row; i < row+perRow && i < len(r.shadow.Bytes); i++ {
+			switch {
+			case i == r.Index:
+				fmt.Fprintf(&b, "[%02x]", r.shadow.Bytes[i])
+			case i == r.Index+1:
+				fmt.Fprintf(&b, "%02x", r.shadow.Bytes[i])
+			default:
+				fmt.Fprintf(&b, " %02x", r.shadow.Bytes[i])
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Shadow byte legend: addressable: %02x  stack mid redzone: %02x  stack right redzone: %02x\n",
+		ShadowAddressable, ShadowMidRedzone, ShadowRightRedzone)
+	return b.String()
+}
@@ -0,0 +1,297 @@
+// Package sim 是 docs/app.js 中“概念栈帧模型”的 Go 移植。
+//
+// 与 frame 包（真实的 Go 结构体 + unsafe 写）不同，这里用一个线性 byte 数组
+// 模拟典型栈帧的布局：
+//
+//	buf(16) → canary(8) → saved RBP(8) → return address(8)
+//
+// 字段变化检测与结论（verdict）的判定顺序与网页完全一致，
+// 共享测试向量见 vectors.json，两边都可以用它互相校验。
+//
+// 除默认布局外，也可以用 JSON 文件描述任意布局（见 Layout / LoadLayout），
+// 在多个局部变量、填充和多个哨兵字段上执行同样的越界写过程。
+package sim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+)
+
+// 段名，与 app.js 中的 key 保持一致。
+const (
+	SegBuf    = "buf"
+	SegCanary = "canary"
+	SegRBP    = "rbp"
+	SegRet    = "ret"
+	SegOOB    = "oob" // 超出栈帧模型范围
+)
+
+// 各段大小（SIZES）。
+const (
+	SizeBuf    = 16
+	SizeCanary = 8
+	SizeRBP    = 8
+	SizeRet    = 8
+)
+
+// 各段起始偏移（OFFSETS）。
+const (
+	OffBuf    = 0
+	OffCanary = SizeBuf
+	OffRBP    = SizeBuf + SizeCanary
+	OffRet    = SizeBuf + SizeCanary + SizeRBP
+)
+
+// FrameLen 是整个栈帧模型的字节数（FRAME_LEN）。
+const FrameLen = SizeBuf + SizeCanary + SizeRBP + SizeRet
+
+// 初始 saved RBP / return address：看起来像指针的数值，不对应真实地址。
+const (
+	InitialRBP uint64 = 0x00007ffff0f0f0f0
+	InitialRet uint64 = 0x0000555511112222
+)
+
+// 结论级别。
+const (
+	LevelOK   = "ok"
+	LevelWarn = "warn"
+	LevelBad  = "bad"
+)
+
+// Mitigations 是概念上的防护开关，只影响模拟结论。
+type Mitigations struct {
+	Canary bool `json:"canary"`
+	NX     bool `json:"nx"`
+	ASLR   bool `json:"aslr"`
+}
+
+// DefaultMitigations 与网页默认勾选状态一致（全部开启）。
+var DefaultMitigations = Mitigations{Canary: true, NX: true, ASLR: true}
+
+// Verdict 是“返回时会发生什么”的概念化结论。
+type Verdict struct {
+	Level string `json:"level"`
+	Text  string `json:"text"`
+}
+
+// Frame 是一次模拟中的栈帧状态。
+type Frame struct {
+	Layout  *Layout
+	Mem     []byte
+	Written []bool
+
+	initial []byte
+	plan    []byte
+	cursor  int
+}
+
+// NewFrame 对应 createFrame：在默认布局上，buf 清零，canary 使用给定的 8 字节，
+// saved RBP / return address 填入固定的示意值。
+func NewFrame(canary [SizeCanary]byte) *Frame {
+	f := NewLayoutFrame(DefaultLayout(), nil)
+	copy(f.Mem[OffCanary:], canary[:])
+	copy(f.initial[OffCanary:], canary[:])
+	return f
+}
+
+// NewLayoutFrame 按布局创建栈帧；init 为 "random" 的段使用 r 生成（r 为 nil 时全 0）。
+func NewLayoutFrame(l *Layout, r *rand.Rand) *Frame {
+	mem := l.initial(r)
+	return &Frame{
+		Layout:  l,
+		Mem:     mem,
+		Written: make([]bool, l.Size()),
+		initial: append([]byte(nil), mem...),
+	}
+}
+
+// RandomCanary 用伪随机字节生成 canary（教育演示，不是安全讨论重点）。
+func RandomCanary(r *rand.Rand) [SizeCanary]byte {
+	var c [SizeCanary]byte
+	for i := range c {
+		c[i] = byte(r.Intn(256))
+	}
+	return c
+}
+
+// SegmentAt 对应 segmentNameAtIndex（默认布局）；超出 FrameLen 时返回 SegOOB。
+func SegmentAt(i int) string {
+	switch {
+	case i >= FrameLen:
+		return SegOOB
+	case i < OffCanary:
+		return SegBuf
+	case i < OffRBP:
+		return SegCanary
+	case i < OffRet:
+		return SegRBP
+	default:
+		return SegRet
+	}
+}
+
+// SegmentShort 对应 segmentShort，返回默认布局中段的简短显示名。
+func SegmentShort(seg string) string {
+	switch seg {
+	case SegBuf:
+		return "buf"
+	case SegCanary:
+		return "canary"
+	case SegRBP:
+		return "saved RBP"
+	case SegRet:
+		return "return addr"
+	}
+	return "未知字段"
+}
+
+// SetPlan 设置本轮要写入的字节（从目标段首字节开始），并清空写入标记与游标。
+func (f *Frame) SetPlan(plan []byte) {
+	f.plan = append([]byte(nil), plan...)
+	f.cursor = 0
+	for i := range f.Written {
+		f.Written[i] = false
+	}
+}
+
+// Step 对应 applyOne：写入计划中的下一个字节。
+// 返回写入的绝对下标与所在段；计划已写完时 ok 为 false。
+func (f *Frame) Step() (idx int, seg string, ok bool) {
+	if f.cursor >= len(f.plan) {
+		return 0, "", false
+	}
+	idx = f.Layout.TargetOffset() + f.cursor
+	if idx < len(f.Mem) {
+		f.Mem[idx] = f.plan[f.cursor]
+		f.Written[idx] = true
+	}
+	f.cursor++
+	return idx, f.Layout.SegmentAt(idx), true
+}
+
+// Run 对应 applyAll：一次写完剩余计划，返回执行的步数（包括超出帧、被丢弃的字节）。
+func (f *Frame) Run() int {
+	n := 0
+	for {
+		if _, _, ok := f.Step(); !ok {
+			return n
+		}
+		n++
+	}
+}
+
+// Cursor 返回下一次写入的偏移（相对写入起点）。
+func (f *Frame) Cursor() int { return f.cursor }
+
+// PlanLen 返回本轮计划写入的字节数。
+func (f *Frame) PlanLen() int { return len(f.plan) }
+
+// Segment 返回某段当前内容的拷贝。
+func (f *Frame) Segment(name string) []byte {
+	s, ok := f.Layout.Segment(name)
+	if !ok {
+		return nil
+	}
+	return append([]byte(nil), f.Mem[s.Offset:s.Offset+s.Size]...)
+}
+
+// SegmentChanged 报告某段是否与初始值不同。
+func (f *Frame) SegmentChanged(name string) bool {
+	s, ok := f.Layout.Segment(name)
+	if !ok {
+		return false
+	}
+	end := s.Offset + s.Size
+	return !bytes.Equal(f.Mem[s.Offset:end], f.initial[s.Offset:end])
+}
+
+// Status 对应 computeStatus 的返回值（不含仅用于展示的地址部分）。
+//
+// 对任意布局：CanaryChanged 表示任一 protected 段被改写，RBPChanged / RetChanged
+// 分别对应 frame-pointer / return-address 段，Changed 列出所有被改写的段。
+type Status struct {
+	CanaryChanged bool     `json:"canaryChanged"`
+	RBPChanged    bool     `json:"rbpChanged"`
+	RetChanged    bool     `json:"retChanged"`
+	LocalChanged  bool     `json:"localChanged"` // 写入目标以外的普通局部变量被改写
+	Changed       []string `json:"changed"`
+	Verdict       Verdict  `json:"verdict"`
+	WrittenCount  int      `json:"writtenCount"`
+	CanaryNow     uint64   `json:"canaryNow"` // 默认布局：u64 LE
+	RetNow        uint64   `json:"retNow"`    // 默认布局：u64 LE
+
+	Cursor   int    `json:"cursor"`
+	PlanLen  int    `json:"planLen"`
+	Started  bool   `json:"started"`
+	Finished bool   `json:"finished"`
+	NextSeg  string `json:"nextSeg,omitempty"` // 空串对应 JS 的 null
+	LastSeg  string `json:"lastSeg,omitempty"`
+}
+
+// ComputeStatus 对应 computeStatus：比较各段与初始值，并给出概念化结论。
+func (f *Frame) ComputeStatus(m Mitigations) Status {
+	var s Status
+	for _, seg := range f.Layout.Segments {
+		if !f.SegmentChanged(seg.Name) {
+			continue
+		}
+		s.Changed = append(s.Changed, seg.Name)
+		switch {
+		case seg.Protected:
+			s.CanaryChanged = true
+		case seg.Kind == KindFramePointer:
+			s.RBPChanged = true
+		case seg.Kind == KindReturnAddress:
+			s.RetChanged = true
+		case seg.Kind == KindLocal && seg.Name != f.Layout.Target:
+			s.LocalChanged = true
+		}
+	}
+	if seg, ok := f.Layout.Segment(SegCanary); ok && seg.Size == 8 {
+		s.CanaryNow = binary.LittleEndian.Uint64(f.Mem[seg.Offset:])
+	}
+	if seg, ok := f.Layout.Segment(SegRet); ok && seg.Size == 8 {
+		s.RetNow = binary.LittleEndian.Uint64(f.Mem[seg.Offset:])
+	}
+	s.Verdict = verdict(s, m)
+	for _, w := range f.Written {
+		if w {
+			s.WrittenCount++
+		}
+	}
+
+	base := f.Layout.TargetOffset()
+	s.PlanLen, s.Cursor = len(f.plan), f.cursor
+	if s.Cursor < s.PlanLen {
+		s.NextSeg = f.Layout.SegmentAt(base + s.Cursor)
+	}
+	if s.Cursor > 0 {
+		s.LastSeg = f.Layout.SegmentAt(base + s.Cursor - 1)
+	}
+	s.Finished = s.PlanLen > 0 && s.Cursor >= s.PlanLen
+	s.Started = s.Cursor > 0
+	return s
+}
+
+// verdict 的判定顺序必须与 app.js 的 computeStatus 保持一致；
+// 最后一条（其他局部变量被改写）只会出现在自定义布局中。
+func verdict(s Status, m Mitigations) Verdict {
+	switch {
+	case m.Canary && s.CanaryChanged:
+		return Verdict{LevelBad, "检测到 Canary 被改写 → 立即终止（模拟）"}
+	case s.RetChanged:
+		// 这里不做“跳到哪里”“怎么构造”的指导，只用抽象描述
+		if m.NX {
+			return Verdict{LevelWarn, "返回地址异常 → 可能崩溃；且 NX 降低栈上执行可能（模拟）"}
+		}
+		return Verdict{LevelWarn, "返回地址异常 → 控制流不可预测（模拟）"}
+	case s.RBPChanged:
+		return Verdict{LevelWarn, "栈帧指针被破坏 → 后续访问局部变量/返回过程可能异常（模拟）"}
+	case s.CanaryChanged:
+		return Verdict{LevelWarn, "相邻内存被覆盖（Canary 改写），但未启用校验（模拟）"}
+	case s.LocalChanged:
+		return Verdict{LevelWarn, "相邻局部变量被覆盖 → 程序逻辑可能出错（模拟）"}
+	}
+	return Verdict{LevelOK, "正常返回（模拟）"}
+}
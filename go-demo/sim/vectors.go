@@ -0,0 +1,86 @@
+package sim
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// vectorsJSON 是 Go 与 docs/app.js 共用的测试向量（docs/check-vectors.js 读取同一文件）。
+//
+//go:embed vectors.json
+var vectorsJSON []byte
+
+// Vector 描述一次“从 buf[0] 写入若干字节”的输入与期望结论。
+type Vector struct {
+	Name        string      `json:"name"`
+	Canary      string      `json:"canary"` // 8 字节 hex
+	Write       string      `json:"write"`  // 从 buf[0] 起写入的字节（hex）
+	Mitigations Mitigations `json:"mitigations"`
+	Expect      struct {
+		CanaryChanged bool   `json:"canaryChanged"`
+		RBPChanged    bool   `json:"rbpChanged"`
+		RetChanged    bool   `json:"retChanged"`
+		Level         string `json:"level"`
+		Text          string `json:"text"`
+		WrittenCount  int    `json:"writtenCount"`
+	} `json:"expect"`
+}
+
+// Vectors 返回内置的共享测试向量。
+func Vectors() ([]Vector, error) {
+	var vs []Vector
+	if err := json.Unmarshal(vectorsJSON, &vs); err != nil {
+		return nil, fmt.Errorf("sim: parse vectors: %w", err)
+	}
+	return vs, nil
+}
+
+// Check 在 Go 模型上执行向量，不符合期望时返回描述差异的错误。
+func (v Vector) Check() error {
+	cb, err := hex.DecodeString(v.Canary)
+	if err != nil || len(cb) != SizeCanary {
+		return fmt.Errorf("%s: bad canary %q", v.Name, v.Canary)
+	}
+	plan, err := hex.DecodeString(v.Write)
+	if err != nil {
+		return fmt.Errorf("%s: bad write: %w", v.Name, err)
+	}
+
+	f := NewFrame([SizeCanary]byte(cb))
+	f.SetPlan(plan)
+	f.Run()
+	s := f.ComputeStatus(v.Mitigations)
+
+	e := v.Expect
+	var errs []error
+	check := func(field string, got, want any) {
+		if got != want {
+			errs = append(errs, fmt.Errorf("%s: %s = %v, want %v", v.Name, field, got, want))
+		}
+	}
+	check("canaryChanged", s.CanaryChanged, e.CanaryChanged)
+	check("rbpChanged", s.RBPChanged, e.RBPChanged)
+	check("retChanged", s.RetChanged, e.RetChanged)
+	check("verdict.level", s.Verdict.Level, e.Level)
+	check("verdict.text", s.Verdict.Text, e.Text)
+	check("writtenCount", s.WrittenCount, e.WrittenCount)
+	return errors.Join(errs...)
+}
+
+// CheckVectors 依次执行全部内置向量，返回所有不符合期望的差异。
+func CheckVectors() error {
+	vs, err := Vectors()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, v := range vs {
+		if err := v.Check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
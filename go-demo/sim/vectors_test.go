@@ -0,0 +1,28 @@
+package sim
+
+import "testing"
+
+// TestVectors 在 Go 模型上执行 vectors.json 中的每个向量，任何一项与期望不符都失败。
+func TestVectors(t *testing.T) {
+	vs, err := Vectors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) == 0 {
+		t.Fatal("no vectors in vectors.json")
+	}
+	for _, v := range vs {
+		t.Run(v.Name, func(t *testing.T) {
+			if err := v.Check(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestCheckVectors 覆盖 vectors 命令使用的汇总入口。
+func TestCheckVectors(t *testing.T) {
+	if err := CheckVectors(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,30 @@
+package sim
+
+// WalkStep 记录越界写过程中的一个字节。
+type WalkStep struct {
+	Cursor  int    `json:"cursor"`  // 相对写入起点的偏移（0-based）
+	Index   int    `json:"index"`   // 在布局中的绝对下标
+	Segment string `json:"segment"` // 落在哪个段；超出布局时为 SegOOB
+	Value   byte   `json:"value"`
+	Crossed bool   `json:"crossed"` // 是否为第一次离开写入目标段的那个字节
+}
+
+// Walk 在 f 的布局上执行“从目标段首字节连续写入 plan”的过程，
+// 返回每个字节的落点；写完后可用 ComputeStatus 查看结论。
+func (f *Frame) Walk(plan []byte) []WalkStep {
+	f.SetPlan(plan)
+	steps := make([]WalkStep, 0, len(plan))
+	crossed := false
+	for {
+		cur := f.Cursor()
+		idx, seg, ok := f.Step()
+		if !ok {
+			return steps
+		}
+		st := WalkStep{Cursor: cur, Index: idx, Segment: seg, Value: plan[cur]}
+		if seg != f.Layout.Target && !crossed {
+			st.Crossed, crossed = true, true
+		}
+		steps = append(steps, st)
+	}
+}
@@ -0,0 +1,88 @@
+package stepper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+const help = "keys: [space/enter/n] step  [b/backspace] back  [a] run all  [r] reset  [q] quit"
+
+// Interactive 在终端中运行单步模式，直到用户按 q（或 Ctrl-C / 输入结束）。
+//
+// 标准输入是终端时切换到非规范模式，每个按键立即生效；否则按行读取命令。
+func Interactive(s *Stepper, in *os.File, out io.Writer, color bool) error {
+	restore, err := makeRaw(in)
+	raw := err == nil
+	if raw {
+		defer restore()
+	}
+
+	r := bufio.NewReader(in)
+	msg := ""
+	for {
+		s.Render(out, color)
+		if msg != "" {
+			fmt.Fprintln(out, msg)
+		}
+		fmt.Fprintln(out, help)
+
+		key, err := readKey(r, raw)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var quit bool
+		if msg, quit = s.handleKey(key); quit {
+			return nil
+		}
+	}
+}
+
+// handleKey 执行一个按键对应的操作，返回要在下一次重绘后显示的提示；quit 为 true 表示退出。
+// 它只改变 Stepper 的状态，不碰终端，单步、回退、重置的状态转换都在这里。
+func (s *Stepper) handleKey(key byte) (msg string, quit bool) {
+	switch key {
+	case ' ', '\r', '\n', 'n', 'l':
+		ok, err := s.Step()
+		switch {
+		case err != nil:
+			msg = fmt.Sprintf("buf+%d is outside the frame: not written", s.Cursor())
+		case !ok:
+			msg = "plan finished: press r to reset"
+		case s.Crossed():
+			msg = ">> this byte crossed len(buf): it landed in canary[0]"
+		}
+	case 'b', 'h', 0x7f, 0x08:
+		if ok, _ := s.Back(); !ok {
+			msg = "nothing to undo"
+		}
+	case 'a':
+		n, _ := s.RunAll()
+		msg = fmt.Sprintf("wrote %d bytes", n)
+	case 'r':
+		s.Reset()
+		msg = "frame reset: ready to write from buf[0]"
+	case 'q', 0x03, 0x04:
+		return "", true
+	}
+	return msg, false
+}
+
+// readKey 读取一个按键；行模式下取该行第一个字符，空行视为回车。
+func readKey(r *bufio.Reader, raw bool) (byte, error) {
+	if raw {
+		return r.ReadByte()
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && line == "" {
+		return 0, err
+	}
+	if len(line) == 0 || line[0] == '\n' {
+		return '\n', nil
+	}
+	return line[0], nil
+}
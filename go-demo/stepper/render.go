@@ -0,0 +1,87 @@
+package stepper
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"shijian/frame"
+)
+
+// ANSI 颜色。
+const (
+	ansiReset   = "\x1b[0m"
+	ansiRed     = "\x1b[41;97m" // 本轮写入覆盖过的字节
+	ansiCursor  = "\x1b[7m"     // 下一次写入的位置
+	ansiBuf     = "\x1b[36m"
+	ansiCanary  = "\x1b[33m"
+	ansiBad     = "\x1b[1;31m"
+	ansiOK      = "\x1b[1;32m"
+	clearScreen = "\x1b[H\x1b[2J"
+)
+
+// Render 把当前状态画到 w：buf 与 canary 两行 hex 视图、写入指针和结论。
+// color 为 false 时不输出任何 ANSI 转义序列（也不清屏），适合重定向到文件。
+func (s *Stepper) Render(w io.Writer, color bool) {
+	var b strings.Builder
+	paint := func(code, text string) string {
+		if !color {
+			return text
+		}
+		return code + text + ansiReset
+	}
+
+	if color {
+		b.WriteString(clearScreen)
+	}
+	snap := s.f.Snapshot()
+	fmt.Fprintf(&b, "frame{buf [%d]byte; canary uint64}  written %d/%d\n\n", frame.BufSize, s.cursor, len(s.plan))
+
+	for _, fl := range frame.Layout() {
+		code := ansiBuf
+		if fl.Name == "canary" {
+			code = ansiCanary
+		}
+		fmt.Fprintf(&b, "%s ", paint(code, fmt.Sprintf("%-6s", fl.Name)))
+		for i := int(fl.Offset); i < int(fl.Offset+fl.Size); i++ {
+			cell := fmt.Sprintf("%02x", snap.Raw[i])
+			switch {
+			case i == s.cursor && !s.Done():
+				cell = paint(ansiCursor, cell)
+			case i < s.cursor:
+				cell = paint(ansiRed, cell)
+			}
+			if !color && i < s.cursor {
+				cell += "*"
+			} else {
+				cell += " "
+			}
+			b.WriteString(cell)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case s.Done():
+		fmt.Fprintf(&b, "next  : (plan finished)\n")
+	case s.cursor >= frame.Size:
+		fmt.Fprintf(&b, "next  : buf+%d (outside frame, will not be written)\n", s.cursor)
+	default:
+		name, idx := frame.FieldAt(s.cursor)
+		fmt.Fprintf(&b, "next  : buf+%d -> %s[%d] = %02x\n", s.cursor, name, idx, s.plan[s.cursor])
+	}
+	fmt.Fprintf(&b, "canary: 0x%016x (initial 0x%016x)\n", s.f.Canary(), s.f.InitialCanary())
+	if s.f.CanaryIntact() {
+		fmt.Fprintf(&b, "status: %s\n", paint(ansiOK, "canary intact"))
+	} else {
+		fmt.Fprintf(&b, "status: %s\n", paint(ansiBad, "canary corrupted"))
+	}
+	if s.cursor > frame.BufSize {
+		fmt.Fprintf(&b, "%s\n", paint(ansiBad, fmt.Sprintf(">> write crossed len(buf)=%d: adjacent field overwritten", frame.BufSize)))
+	}
+	io.WriteString(w, b.String())
+}
+
+// Crossed 报告最近一次写入是否恰好是越过 len(buf) 的第一个字节。
+func (s *Stepper) Crossed() bool { return s.cursor == frame.BufSize+1 }
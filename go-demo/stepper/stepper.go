@@ -0,0 +1,89 @@
+// Package stepper 是网页“单步写 1 字节”按钮的终端版本：每按一次键，
+// 就在真实的 frame 结构体上用 unsafe 写入 1 个字节，并重绘 buf / canary 的 hex 视图。
+// 适用于只能通过 SSH 登录、无法打开网页的实验机器。
+package stepper
+
+import (
+	"errors"
+
+	"shijian/frame"
+)
+
+// Stepper 在一个 frame 上按计划逐字节写入，并记录被覆盖的旧值以支持回退。
+type Stepper struct {
+	f      *frame.Frame
+	plan   []byte
+	cursor int
+	prev   []byte // prev[i] 是第 i 次写入前该位置的旧值
+}
+
+// New 创建一个在 f 上写入 plan 的 Stepper。
+func New(f *frame.Frame, plan []byte) *Stepper {
+	return &Stepper{f: f, plan: append([]byte(nil), plan...)}
+}
+
+// Frame 返回被写入的 frame。
+func (s *Stepper) Frame() *frame.Frame { return s.f }
+
+// Plan 返回计划写入的全部字节。
+func (s *Stepper) Plan() []byte { return s.plan }
+
+// Cursor 返回下一次写入的偏移（相对 buf[0]），也等于已写入的字节数。
+func (s *Stepper) Cursor() int { return s.cursor }
+
+// Done 报告计划是否已全部写完。
+func (s *Stepper) Done() bool { return s.cursor >= len(s.plan) }
+
+// Step 写入下一个字节。计划已写完时返回 false；
+// 写入位置超出 frame 时返回 frame.ErrOutOfFrame（游标不前进）。
+func (s *Stepper) Step() (bool, error) {
+	if s.Done() {
+		return false, nil
+	}
+	old := s.f.Snapshot().Raw
+	if s.cursor >= len(old) {
+		return false, frame.ErrOutOfFrame
+	}
+	if _, err := s.f.WriteAt(s.plan[s.cursor:s.cursor+1], int64(s.cursor)); err != nil {
+		return false, err
+	}
+	s.prev = append(s.prev, old[s.cursor])
+	s.cursor++
+	return true, nil
+}
+
+// Back 撤销最近一次写入，恢复该位置的旧值。没有可撤销的写入时返回 false。
+func (s *Stepper) Back() (bool, error) {
+	if s.cursor == 0 {
+		return false, nil
+	}
+	last := len(s.prev) - 1
+	if _, err := s.f.WriteAt(s.prev[last:], int64(s.cursor-1)); err != nil {
+		return false, err
+	}
+	s.prev = s.prev[:last]
+	s.cursor--
+	return true, nil
+}
+
+// Reset 把 frame 恢复到初始状态并把游标移回 buf[0]。
+func (s *Stepper) Reset() {
+	s.f.Reset()
+	s.cursor = 0
+	s.prev = s.prev[:0]
+}
+
+// RunAll 写完剩余计划，返回本次写入的字节数。
+func (s *Stepper) RunAll() (int, error) {
+	n := 0
+	for {
+		ok, err := s.Step()
+		if err != nil && !errors.Is(err, frame.ErrOutOfFrame) {
+			return n, err
+		}
+		if !ok {
+			return n, err
+		}
+		n++
+	}
+}
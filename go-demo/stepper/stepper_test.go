@@ -0,0 +1,123 @@
+package stepper
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"shijian/frame"
+)
+
+// newTest 返回一个 Stepper，计划比整个 frame 多写 4 个字节。
+func newTest(t *testing.T) (*Stepper, []byte) {
+	t.Helper()
+	f := frame.New(frame.DefaultCanary)
+	plan := bytes.Repeat([]byte{'A'}, frame.Size+4)
+	return New(f, plan), raw(f)
+}
+
+func raw(f *frame.Frame) []byte {
+	r := f.Snapshot().Raw
+	return r[:]
+}
+
+func press(t *testing.T, s *Stepper, keys string) string {
+	t.Helper()
+	var msg string
+	for i := 0; i < len(keys); i++ {
+		var quit bool
+		if msg, quit = s.handleKey(keys[i]); quit {
+			t.Fatalf("key %q quit", keys[i])
+		}
+	}
+	return msg
+}
+
+func TestStepAndBack(t *testing.T) {
+	s, initial := newTest(t)
+	if msg := press(t, s, "b"); msg != "nothing to undo" {
+		t.Errorf("back at buf[0]: msg %q", msg)
+	}
+	press(t, s, "nnn")
+	if s.Cursor() != 3 {
+		t.Fatalf("cursor after 3 steps = %d, want 3", s.Cursor())
+	}
+	press(t, s, "bb")
+	if s.Cursor() != 1 {
+		t.Fatalf("cursor after 2 backs = %d, want 1", s.Cursor())
+	}
+	want := append([]byte{'A'}, initial[1:]...)
+	if got := raw(s.Frame()); !bytes.Equal(got, want) {
+		t.Errorf("frame after step, step, step, back, back = % x, want % x", got, want)
+	}
+}
+
+func TestCrossedMessage(t *testing.T) {
+	s, _ := newTest(t)
+	for i := 0; i < frame.BufSize; i++ {
+		press(t, s, "n")
+	}
+	if msg := press(t, s, "n"); msg != ">> this byte crossed len(buf): it landed in canary[0]" {
+		t.Errorf("step into canary[0]: msg %q", msg)
+	}
+}
+
+func TestBackAfterRunAll(t *testing.T) {
+	s, initial := newTest(t)
+	press(t, s, "nn")
+	if msg, want := press(t, s, "a"), fmt.Sprintf("wrote %d bytes", frame.Size-2); msg != want {
+		t.Errorf("run all: msg %q, want %q", msg, want)
+	}
+	if s.Cursor() != frame.Size {
+		t.Fatalf("cursor after run all = %d, want %d (the rest of the plan is outside the frame)", s.Cursor(), frame.Size)
+	}
+	if s.Frame().CanaryIntact() {
+		t.Fatal("canary intact after run all")
+	}
+	if msg, want := press(t, s, "n"), fmt.Sprintf("buf+%d is outside the frame: not written", frame.Size); msg != want {
+		t.Errorf("step past the frame: msg %q, want %q", msg, want)
+	}
+
+	// 一路回退到 buf[0]：每个字节都恢复成写入前的值。
+	for i := frame.Size; i > 0; i-- {
+		press(t, s, "b")
+		got := raw(s.Frame())
+		if got[i-1] != initial[i-1] {
+			t.Fatalf("back to cursor %d: byte %d = %02x, want %02x", i-1, i-1, got[i-1], initial[i-1])
+		}
+	}
+	if !bytes.Equal(raw(s.Frame()), initial) || !s.Frame().CanaryIntact() {
+		t.Errorf("frame after backing out of run all differs from the initial frame")
+	}
+	if msg := press(t, s, "b"); msg != "nothing to undo" {
+		t.Errorf("back at buf[0]: msg %q", msg)
+	}
+}
+
+func TestReset(t *testing.T) {
+	s, initial := newTest(t)
+	press(t, s, "a")
+	if msg := press(t, s, "r"); msg != "frame reset: ready to write from buf[0]" {
+		t.Errorf("reset: msg %q", msg)
+	}
+	if s.Cursor() != 0 || !bytes.Equal(raw(s.Frame()), initial) {
+		t.Fatalf("after reset: cursor %d, frame % x; want 0, % x", s.Cursor(), raw(s.Frame()), initial)
+	}
+	if msg := press(t, s, "b"); msg != "nothing to undo" {
+		t.Errorf("back right after reset: msg %q", msg)
+	}
+	// 重置后可以重新写，回退记录从头开始。
+	press(t, s, "nnb")
+	if s.Cursor() != 1 || raw(s.Frame())[0] != 'A' || raw(s.Frame())[1] != initial[1] {
+		t.Errorf("step, step, back after reset: cursor %d, frame % x", s.Cursor(), raw(s.Frame())[:2])
+	}
+}
+
+func TestQuit(t *testing.T) {
+	s, _ := newTest(t)
+	for _, k := range []byte{'q', 0x03, 0x04} {
+		if _, quit := s.handleKey(k); !quit {
+			t.Errorf("key %#x did not quit", k)
+		}
+	}
+}
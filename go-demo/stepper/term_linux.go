@@ -0,0 +1,34 @@
+//go:build linux
+
+package stepper
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw 关闭终端的行缓冲、回显和信号键，让每个按键立即可读。
+// 标准输入不是终端时返回错误，调用方改用按行读取。
+func makeRaw(f *os.File) (restore func(), err error) {
+	fd := f.Fd()
+	var old syscall.Termios
+	if err := ioctl(fd, syscall.TCGETS, &old); err != nil {
+		return nil, err
+	}
+	t := old
+	t.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ISIG
+	t.Cc[syscall.VMIN] = 1
+	t.Cc[syscall.VTIME] = 0
+	if err := ioctl(fd, syscall.TCSETS, &t); err != nil {
+		return nil, err
+	}
+	return func() { ioctl(fd, syscall.TCSETS, &old) }, nil
+}
+
+func ioctl(fd uintptr, req uint, t *syscall.Termios) error {
+	if _, _, e := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(req), uintptr(unsafe.Pointer(t))); e != 0 {
+		return e
+	}
+	return nil
+}
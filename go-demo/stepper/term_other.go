@@ -0,0 +1,13 @@
+//go:build !linux
+
+package stepper
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw 在非 Linux 平台上不切换终端模式，Interactive 会按行读取命令。
+func makeRaw(*os.File) (func(), error) {
+	return nil, errors.New("stepper: raw terminal mode not supported on this platform")
+}
@@ -0,0 +1,138 @@
+package typelayout
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// 越界写触及的字节类别。
+const (
+	ClassField   = "field"
+	ClassPadding = "padding"          // 字段之间的填充
+	ClassTail    = "trailing-padding" // 结构体（或嵌套结构体、数组元素）末尾的填充
+	ClassOutside = "outside"          // 超出整个对象
+)
+
+// ByteHit 是一次写入触及的一个字节。
+type ByteHit struct {
+	Offset  uintptr `json:"offset"`
+	Before  byte    `json:"before"`
+	After   byte    `json:"after"`
+	Class   string  `json:"class"`
+	Where   string  `json:"where"` // 字段路径加字段内下标，如 "id[3]"；填充为其所在结构体
+	Changed bool    `json:"changed"`
+}
+
+// FieldHit 汇总一个字段被触及和被改变的字节数。
+type FieldHit struct {
+	Path    string `json:"path"`
+	Touched int    `json:"touched"`
+	Changed int    `json:"changed"`
+}
+
+// CorruptionMap 把一次写入的每个字节归类到字段、字段间填充或末尾填充。
+type CorruptionMap struct {
+	Type    string     `json:"type"`
+	Start   uintptr    `json:"start"`
+	Len     int        `json:"len"`
+	Bytes   []ByteHit  `json:"bytes"`
+	Fields  []FieldHit `json:"fields"`  // 按首次触及的顺序
+	Padding int        `json:"padding"` // 被填充“吸收”的字节数（两类填充之和）
+	Tail    int        `json:"tail"`    // 其中落在末尾填充的字节数
+	Outside int        `json:"outside"`
+}
+
+// Map 根据布局 r 以及写入前后的对象字节，对从 start 开始写入的 n 个字节逐一归类。
+// before / after 是整个对象的字节（长度通常等于 r.Size）。
+func (r *Report) Map(start uintptr, n int, before, after []byte) *CorruptionMap {
+	m := &CorruptionMap{Type: r.Type, Start: start, Len: n}
+	index := map[string]int{}
+	for i := 0; i < n; i++ {
+		off := start + uintptr(i)
+		h := ByteHit{Offset: off, Class: ClassOutside}
+		if int(off) < len(before) && int(off) < len(after) {
+			h.Before, h.After = before[off], after[off]
+			h.Changed = h.Before != h.After
+		}
+		e, ok := r.At(off)
+		switch {
+		case !ok:
+			m.Outside++
+		case e.Kind == KindField:
+			h.Class, h.Where = ClassField, fmt.Sprintf("%s[%d]", e.Path, off-e.Offset)
+			j, seen := index[e.Path]
+			if !seen {
+				j = len(m.Fields)
+				index[e.Path] = j
+				m.Fields = append(m.Fields, FieldHit{Path: e.Path})
+			}
+			m.Fields[j].Touched++
+			if h.Changed {
+				m.Fields[j].Changed++
+			}
+		default:
+			h.Class, h.Where = ClassPadding, e.Path
+			if e.Kind == KindTail {
+				h.Class = ClassTail
+				m.Tail++
+			}
+			m.Padding++
+		}
+		m.Bytes = append(m.Bytes, h)
+	}
+	return m
+}
+
+// WriteTable 输出逐字节的归类表和摘要。
+func (m *CorruptionMap) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "type %s: %d bytes written at offset %d\n\n", m.Type, m.Len, m.Start)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OFFSET\tBEFORE\tAFTER\tCLASS\tWHERE\t")
+	for _, h := range m.Bytes {
+		mark := ""
+		if h.Changed {
+			mark = "changed"
+		}
+		fmt.Fprintf(tw, "%d\t%02x\t%02x\t%s\t%s\t%s\n", h.Offset, h.Before, h.After, h.Class, h.Where, mark)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nSummary:\n")
+	for _, s := range m.Summary() {
+		fmt.Fprintf(w, "  %s\n", s)
+	}
+	return nil
+}
+
+// Summary 返回“N bytes of padding absorbed, M bytes of field X changed”形式的摘要行。
+func (m *CorruptionMap) Summary() []string {
+	var out []string
+	if m.Padding > 0 {
+		s := fmt.Sprintf("%d bytes of padding absorbed", m.Padding)
+		if m.Tail > 0 {
+			s += fmt.Sprintf(" (%d of them trailing padding)", m.Tail)
+		}
+		out = append(out, s)
+	}
+	for _, f := range m.Fields {
+		switch {
+		case f.Changed > 0:
+			out = append(out, fmt.Sprintf("%d bytes of field %s changed", f.Changed, f.Path))
+		default:
+			out = append(out, fmt.Sprintf("%d bytes of field %s written with the same value (unchanged)", f.Touched, f.Path))
+		}
+	}
+	if m.Outside > 0 {
+		out = append(out, fmt.Sprintf("%d bytes fell outside the object and were not written", m.Outside))
+	}
+	if len(out) == 0 {
+		out = append(out, "nothing written")
+	}
+	return out
+}
+
+// String 返回单行摘要。
+func (m *CorruptionMap) String() string { return strings.Join(m.Summary(), ", ") }
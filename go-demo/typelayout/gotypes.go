@@ -0,0 +1,268 @@
+package typelayout
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// DefaultArches 是跨架构比较时默认使用的 GOARCH 列表（32 位与 64 位、大端与小端都有）。
+var DefaultArches = []string{"386", "arm", "mips", "amd64", "arm64", "mips64", "ppc64le", "riscv64", "s390x", "wasm"}
+
+// FromTypes 用 go/types 的 sizes（例如 types.SizesFor("gc", "386")）计算布局，
+// 结果与反射版 Of 的结构相同，但不需要在目标架构上运行。
+func FromTypes(t types.Type, sizes types.Sizes, opts Options) (*Report, error) {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("typelayout: %s is not a struct type", t)
+	}
+	if opts.ExpandArrays == 0 {
+		opts.ExpandArrays = DefaultExpandArrays
+	}
+	r := &Report{Type: t.String(), Size: uintptr(sizes.Sizeof(t)), Align: uintptr(sizes.Alignof(t))}
+	r.walkTypesStruct(st, sizes, "", 0, 0, opts)
+	r.Padding = typesPaddingOf(t, sizes)
+	return r, nil
+}
+
+// typesPaddingOf 是 paddingOf 的 go/types 版本。
+func typesPaddingOf(t types.Type, sizes types.Sizes) uintptr {
+	switch u := t.Underlying().(type) {
+	case *types.Array:
+		return uintptr(u.Len()) * typesPaddingOf(u.Elem(), sizes)
+	case *types.Struct:
+		fields := make([]*types.Var, u.NumFields())
+		for i := range fields {
+			fields[i] = u.Field(i)
+		}
+		offsets := sizes.Offsetsof(fields)
+		pad, end := uintptr(0), uintptr(0)
+		for i, f := range fields {
+			off := uintptr(offsets[i])
+			pad += off - end + typesPaddingOf(f.Type(), sizes)
+			end = off + uintptr(sizes.Sizeof(f.Type()))
+		}
+		return pad + uintptr(sizes.Sizeof(u)) - end
+	}
+	return 0
+}
+
+func (r *Report) walkTypesStruct(st *types.Struct, sizes types.Sizes, prefix string, base uintptr, depth int, opts Options) {
+	fields := make([]*types.Var, st.NumFields())
+	for i := range fields {
+		fields[i] = st.Field(i)
+	}
+	offsets := sizes.Offsetsof(fields)
+	end := uintptr(0)
+	for i, f := range fields {
+		off := uintptr(offsets[i])
+		if off > end {
+			r.pad(KindPadding, prefix+"(padding before "+f.Name()+")", base+end, off-end, depth)
+		}
+		r.walkTypesField(f.Type(), sizes, prefix+f.Name(), base+off, depth, opts)
+		end = off + uintptr(sizes.Sizeof(f.Type()))
+	}
+	if size := uintptr(sizes.Sizeof(st)); size > end {
+		r.pad(KindTail, prefix+"(trailing padding)", base+end, size-end, depth)
+	}
+}
+
+func (r *Report) walkTypesField(t types.Type, sizes types.Sizes, path string, off uintptr, depth int, opts Options) {
+	r.Entries = append(r.Entries, Entry{
+		Path: path, Type: types.TypeString(t, shortQualifier), Kind: KindField,
+		Offset: off, Size: uintptr(sizes.Sizeof(t)), Align: uintptr(sizes.Alignof(t)), Depth: depth,
+	})
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		r.walkTypesStruct(u, sizes, path+".", off, depth+1, opts)
+	case *types.Array:
+		if !typesHasStruct(u.Elem()) || opts.ExpandArrays <= 0 {
+			return
+		}
+		n := int(u.Len())
+		if n > opts.ExpandArrays {
+			n = opts.ExpandArrays
+		}
+		elemSize := uintptr(sizes.Sizeof(u.Elem()))
+		for i := 0; i < n; i++ {
+			r.walkTypesField(u.Elem(), sizes, fmt.Sprintf("%s[%d]", path, i), off+uintptr(i)*elemSize, depth+1, opts)
+		}
+	}
+}
+
+func typesHasStruct(t types.Type) bool {
+	for {
+		a, ok := t.Underlying().(*types.Array)
+		if !ok {
+			break
+		}
+		t = a.Elem()
+	}
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+// shortQualifier 只用包名限定类型，让表格保持紧凑。
+func shortQualifier(p *types.Package) string { return p.Name() }
+
+// LoadSource 对单个 Go 源文件做类型检查，并返回其中名为 name 的类型。
+// 文件的构建约束（如 //go:build ignore）会被忽略；标准库导入从源码解析。
+func LoadSource(path, name string) (types.Type, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("typelayout: type-check %s: %w", path, err)
+	}
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("typelayout: no type %q in %s", name, path)
+	}
+	return obj.Type(), nil
+}
+
+// Cell 是某个架构下一个字段的偏移与大小。
+type Cell struct {
+	Offset uintptr `json:"offset"`
+	Size   uintptr `json:"size"`
+}
+
+// CompareRow 是比较表中的一行（一个字段）。
+type CompareRow struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Depth    int    `json:"depth"`
+	Cells    []Cell `json:"cells"` // 与 Comparison.Arches 一一对应
+	Diverges bool   `json:"diverges"`
+}
+
+// Comparison 是同一结构体在多个架构下的布局对比。
+type Comparison struct {
+	Type     string       `json:"type"`
+	Arches   []string     `json:"arches"`
+	Reports  []*Report    `json:"reports"`
+	Rows     []CompareRow `json:"rows"`
+	Diverges bool         `json:"diverges"` // 任一字段、总大小或对齐不同
+}
+
+// Compare 在每个 GOARCH 的 gc sizes 下计算 t 的布局并逐字段比较。
+func Compare(t types.Type, arches []string, opts Options) (*Comparison, error) {
+	c := &Comparison{Type: t.String(), Arches: arches}
+	for _, arch := range arches {
+		sizes := types.SizesFor("gc", arch)
+		if sizes == nil {
+			return nil, fmt.Errorf("typelayout: unknown GOARCH %q", arch)
+		}
+		r, err := FromTypes(t, sizes, opts)
+		if err != nil {
+			return nil, err
+		}
+		c.Reports = append(c.Reports, r)
+	}
+
+	// 字段集合与顺序只由类型决定，因此各架构的字段条目一一对应。
+	fields := make([][]Entry, len(c.Reports))
+	for i, r := range c.Reports {
+		fields[i] = r.fields()
+	}
+	for fi, e := range fields[0] {
+		row := CompareRow{Path: e.Path, Type: e.Type, Depth: e.Depth}
+		for _, fs := range fields {
+			row.Cells = append(row.Cells, Cell{fs[fi].Offset, fs[fi].Size})
+		}
+		for _, cell := range row.Cells[1:] {
+			if cell != row.Cells[0] {
+				row.Diverges = true
+			}
+		}
+		c.Diverges = c.Diverges || row.Diverges
+		c.Rows = append(c.Rows, row)
+	}
+	for _, r := range c.Reports[1:] {
+		if r.Size != c.Reports[0].Size || r.Align != c.Reports[0].Align {
+			c.Diverges = true
+		}
+	}
+	return c, nil
+}
+
+// fields 返回报告中的字段条目（不含填充）。
+func (r *Report) fields() []Entry {
+	var out []Entry
+	for _, e := range r.Entries {
+		if e.Kind == KindField {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// WriteTable 输出比较表：每个架构一列 "offset+size"，不一致的行以 "*" 标出。
+func (c *Comparison) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "type %s across %d architectures\n\n", c.Type, len(c.Arches))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, " \tFIELD\tTYPE")
+	for _, a := range c.Arches {
+		fmt.Fprintf(tw, "\t%s", a)
+	}
+	fmt.Fprintln(tw)
+
+	mark := func(diverges bool) string {
+		if diverges {
+			return "*"
+		}
+		return " "
+	}
+	for _, row := range c.Rows {
+		fmt.Fprintf(tw, "%s\t%s%s\t%s", mark(row.Diverges), strings.Repeat("  ", row.Depth), row.Path, row.Type)
+		for _, cell := range row.Cells {
+			fmt.Fprintf(tw, "\t%d+%d", cell.Offset, cell.Size)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	summary := func(label string, get func(*Report) uintptr) {
+		vals := make([]uintptr, len(c.Reports))
+		same := true
+		for i, r := range c.Reports {
+			vals[i] = get(r)
+			same = same && vals[i] == vals[0]
+		}
+		fmt.Fprintf(tw, "%s\t(%s)\t", mark(!same), label)
+		for _, v := range vals {
+			fmt.Fprintf(tw, "\t%d", v)
+		}
+		fmt.Fprintln(tw)
+	}
+	summary("size", func(r *Report) uintptr { return r.Size })
+	summary("align", func(r *Report) uintptr { return r.Align })
+	summary("padding", func(r *Report) uintptr { return r.Padding })
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if c.Diverges {
+		_, err := fmt.Fprintln(w, "\n* layout differs between architectures (cells are offset+size)")
+		return err
+	}
+	_, err := fmt.Fprintln(w, "\nlayout is identical on all listed architectures (cells are offset+size)")
+	return err
+}
+
+// WriteJSON 以缩进的 JSON 输出比较结果。
+func (c *Comparison) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
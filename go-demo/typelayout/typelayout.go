@@ -0,0 +1,184 @@
+// Package typelayout 用反射报告任意结构体类型的内存布局：每个字段的偏移、
+// 大小、对齐，以及字段之间和结构体末尾的填充（padding）空洞。
+// 嵌套结构体和结构体数组会被展开，用来回答“谁和谁在内存里相邻”。
+package typelayout
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// 条目种类。
+const (
+	KindField   = "field"
+	KindPadding = "padding"          // 字段之间的填充
+	KindTail    = "trailing-padding" // 结构体末尾的填充
+)
+
+// DefaultExpandArrays 是结构体数组默认展开的元素个数。
+const DefaultExpandArrays = 4
+
+// Entry 是布局中的一行：一个字段或一段填充。偏移都相对最外层结构体起点。
+type Entry struct {
+	Path   string  `json:"path"`           // 例如 "hdr.len"、"items[1].tag"；填充如 "hdr.(padding before len)"
+	Type   string  `json:"type,omitempty"` // 字段类型；填充为空
+	Kind   string  `json:"kind"`
+	Offset uintptr `json:"offset"`
+	Size   uintptr `json:"size"`
+	Align  uintptr `json:"align,omitempty"`
+	Depth  int     `json:"depth"` // 嵌套层级，0 为最外层字段
+}
+
+// End 返回条目之后的第一个字节偏移。
+func (e Entry) End() uintptr { return e.Offset + e.Size }
+
+// Report 是一个结构体类型的完整布局。
+type Report struct {
+	Type    string  `json:"type"`
+	Size    uintptr `json:"size"`
+	Align   uintptr `json:"align"`
+	Padding uintptr `json:"padding"` // 全部填充字节数（含嵌套结构体内部和未展开的数组元素）
+	Entries []Entry `json:"entries"`
+}
+
+// Options 控制报告的展开方式。
+type Options struct {
+	// ExpandArrays 是结构体数组最多展开的元素个数；0 表示使用 DefaultExpandArrays，
+	// 负数表示不展开。未展开的元素作为一个整体条目列出。
+	ExpandArrays int
+}
+
+// Of 用默认选项报告 t 的布局；t 可以是结构体或指向结构体的指针。
+func Of(t reflect.Type) (*Report, error) {
+	return OfWith(t, Options{})
+}
+
+// OfWith 按 opts 报告 t 的布局。
+func OfWith(t reflect.Type, opts Options) (*Report, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("typelayout: %s is not a struct type", t)
+	}
+	if opts.ExpandArrays == 0 {
+		opts.ExpandArrays = DefaultExpandArrays
+	}
+	r := &Report{Type: t.String(), Size: t.Size(), Align: uintptr(t.Align())}
+	r.walkStruct(t, "", 0, 0, opts)
+	r.Padding = paddingOf(t)
+	return r, nil
+}
+
+// paddingOf 返回 t 内部的全部填充字节数，包括嵌套结构体和每一个数组元素，
+// 与 ExpandArrays 展开了多少条目无关。
+func paddingOf(t reflect.Type) uintptr {
+	switch t.Kind() {
+	case reflect.Array:
+		return uintptr(t.Len()) * paddingOf(t.Elem())
+	case reflect.Struct:
+		pad, end := uintptr(0), uintptr(0)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			pad += f.Offset - end + paddingOf(f.Type)
+			end = f.Offset + f.Type.Size()
+		}
+		return pad + t.Size() - end
+	}
+	return 0
+}
+
+// walkStruct 追加结构体 t（位于 base 偏移处）的字段与填充。
+func (r *Report) walkStruct(t reflect.Type, prefix string, base uintptr, depth int, opts Options) {
+	end := uintptr(0)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Offset > end {
+			r.pad(KindPadding, prefix+"(padding before "+f.Name+")", base+end, f.Offset-end, depth)
+		}
+		r.walkField(f.Type, prefix+f.Name, base+f.Offset, depth, opts)
+		end = f.Offset + f.Type.Size()
+	}
+	if t.Size() > end {
+		r.pad(KindTail, prefix+"(trailing padding)", base+end, t.Size()-end, depth)
+	}
+}
+
+// walkField 追加一个字段；结构体与结构体数组会继续向下展开。
+func (r *Report) walkField(t reflect.Type, path string, off uintptr, depth int, opts Options) {
+	r.Entries = append(r.Entries, Entry{
+		Path: path, Type: t.String(), Kind: KindField,
+		Offset: off, Size: t.Size(), Align: uintptr(t.Align()), Depth: depth,
+	})
+	switch {
+	case t.Kind() == reflect.Struct:
+		r.walkStruct(t, path+".", off, depth+1, opts)
+	case t.Kind() == reflect.Array && hasStruct(t.Elem()) && opts.ExpandArrays > 0:
+		n := t.Len()
+		if n > opts.ExpandArrays {
+			n = opts.ExpandArrays
+		}
+		for i := 0; i < n; i++ {
+			r.walkField(t.Elem(), fmt.Sprintf("%s[%d]", path, i), off+uintptr(i)*t.Elem().Size(), depth+1, opts)
+		}
+	}
+}
+
+func (r *Report) pad(kind, path string, off, size uintptr, depth int) {
+	r.Entries = append(r.Entries, Entry{
+		Path: path, Kind: kind,
+		Offset: off, Size: size, Depth: depth,
+	})
+}
+
+// hasStruct 报告 t 是否是（或数组元素是）结构体。
+func hasStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// At 返回覆盖偏移 off 的最深一层条目；off 超出结构体时返回 false。
+func (r *Report) At(off uintptr) (Entry, bool) {
+	var best Entry
+	found := false
+	for _, e := range r.Entries {
+		if off >= e.Offset && off < e.End() && (!found || e.Depth >= best.Depth) {
+			best, found = e, true
+		}
+	}
+	return best, found
+}
+
+// WriteTable 以对齐的文本表格输出布局，嵌套字段按层级缩进。
+func (r *Report) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "type %s  (size=%d, align=%d, padding=%d bytes)\n\n", r.Type, r.Size, r.Align, r.Padding)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OFFSET\tSIZE\tALIGN\tFIELD\tTYPE")
+	for _, e := range r.Entries {
+		align := "-"
+		if e.Kind == KindField {
+			align = fmt.Sprint(e.Align)
+		}
+		name := strings.Repeat("  ", e.Depth) + e.Path
+		typ := e.Type
+		if e.Kind != KindField {
+			name = strings.Repeat("  ", e.Depth) + "<" + e.Kind + ">"
+			typ = ""
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\n", e.Offset, e.Size, align, name, typ)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON 以缩进的 JSON 输出布局。
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
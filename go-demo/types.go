@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"shijian/frame"
+)
+
+// 以下类型只用于演示布局（shijian layout -type <name>），程序里不会真正使用它们的值。
+
+// paddedRecord 的字段顺序故意不好：小字段夹在大字段之间，产生填充。
+type paddedRecord struct {
+	tag  byte
+	id   int64
+	n    int32
+	flag bool
+}
+
+// packedRecord 与 paddedRecord 字段相同，按大小降序排列后填充最少。
+type packedRecord struct {
+	id   int64
+	n    int32
+	tag  byte
+	flag bool
+}
+
+type header struct {
+	kind uint8
+	len  uint16
+}
+
+// packet 包含嵌套结构体、字节数组和指针。
+type packet struct {
+	hdr     header
+	payload [6]byte
+	crc     uint32
+	next    *packet
+}
+
+// table 是结构体数组：一个元素的尾部填充紧挨着下一个元素的首字段。
+type table struct {
+	rows [3]paddedRecord
+}
+
+// demoType 是可以按名字在命令行中引用的类型。
+type demoType struct {
+	t    reflect.Type
+	desc string
+}
+
+var demoTypes = map[string]demoType{
+	"frame":  {frame.Type(), "the demo frame: buf [16]byte followed by canary uint64"},
+	"padded": {reflect.TypeOf(paddedRecord{}), "small fields between large ones (padding holes)"},
+	"packed": {reflect.TypeOf(packedRecord{}), "same fields as padded, sorted by size"},
+	"packet": {reflect.TypeOf(packet{}), "nested struct, byte array and pointer"},
+	"table":  {reflect.TypeOf(table{}), "array of padded records"},
+}
+
+// lookupType 按名字查找演示类型。
+func lookupType(name string) (reflect.Type, error) {
+	if d, ok := demoTypes[name]; ok {
+		return d.t, nil
+	}
+	return nil, fmt.Errorf("unknown type %q (known: %s)", name, strings.Join(demoTypeNames(), ", "))
+}
+
+func demoTypeNames() []string {
+	names := make([]string, 0, len(demoTypes))
+	for n := range demoTypes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,16 @@
+// unsafebounds 以独立命令或 go vet 工具的形式运行 unsafebounds 分析器：
+//
+//	go install shijian/unsafebounds/cmd/unsafebounds
+//	unsafebounds ./...
+//	go vet -vettool=$(which unsafebounds) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"shijian/unsafebounds"
+)
+
+func main() {
+	singlechecker.Main(unsafebounds.Analyzer)
+}
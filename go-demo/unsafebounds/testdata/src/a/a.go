@@ -0,0 +1,67 @@
+package a
+
+import "unsafe"
+
+type frame struct {
+	buf    [16]byte
+	canary uint64
+}
+
+// demo 是 main.go 最初的写法：上界是 payload 的长度，和 buf 的长度无关。
+func demo(f *frame, payload []byte) {
+	base := (*byte)(unsafe.Pointer(&f.buf[0]))
+	for i := 0; i < len(payload); i++ {
+		*(*byte)(unsafe.Pointer(uintptr(unsafe.Pointer(base)) + uintptr(i))) = payload[i] // want `may go past the end of f.buf: loop bound len\(payload\) is not limited to its length 16`
+	}
+}
+
+// constBound 的上界是常量 24，超过 16 字节的 buf。
+func constBound(f *frame) {
+	for i := 0; i < 24; i++ {
+		*(*byte)(unsafe.Add(unsafe.Pointer(&f.buf[0]), i)) = 'A' // want `goes past the end of f.buf: i < 24 reaches byte 23 of a 16-byte array`
+	}
+}
+
+// fromMiddle 从 buf[8] 开始写 12 个字节。
+func fromMiddle(f *frame, src [12]byte) {
+	for i := 0; i < 12; i++ {
+		*(*byte)(unsafe.Pointer(uintptr(unsafe.Pointer(&f.buf[8])) + uintptr(i))) = src[i] // want `goes past the end of f.buf: i < 12 reaches byte 19 of a 16-byte array`
+	}
+}
+
+// words 按元素大小步进，<= 让最后一次写到了第 5 个元素。
+func words(a *[4]uint32) {
+	for i := 0; i <= 4; i++ {
+		*(*uint32)(unsafe.Pointer(uintptr(unsafe.Pointer(&a[0])) + uintptr(i)*unsafe.Sizeof(a[0]))) = 0 // want `goes past the end of a: i <= 4 reaches byte 19 of a 16-byte array`
+	}
+}
+
+// 以下都不报告。
+
+func inBounds(f *frame) {
+	for i := 0; i < 16; i++ {
+		*(*byte)(unsafe.Pointer(uintptr(unsafe.Pointer(&f.buf[0])) + uintptr(i))) = 'A'
+	}
+}
+
+func lenOfArray(f *frame) {
+	for i := 0; i < len(f.buf); i++ {
+		*(*byte)(unsafe.Add(unsafe.Pointer(&f.buf[0]), i)) = 'A'
+	}
+}
+
+// notArray 的基址不是 &arr[k]，长度未知，不做判断。
+func notArray(p unsafe.Pointer, n int) {
+	for i := 0; i < n; i++ {
+		*(*byte)(unsafe.Add(p, i)) = 0
+	}
+}
+
+// reassigned 的基址变量被改过，不追踪。
+func reassigned(f *frame, other *[64]byte, n int) {
+	base := unsafe.Pointer(&f.buf[0])
+	base = unsafe.Pointer(&other[0])
+	for i := 0; i < n; i++ {
+		*(*byte)(unsafe.Add(base, i)) = 0
+	}
+}
@@ -0,0 +1,591 @@
+// Package unsafebounds 定义一个与 go vet 兼容的分析器，查找 shijian 演示（main.go 最初的写法）
+// 里的那种 unsafe 指针运算：
+//
+//	base := (*byte)(unsafe.Pointer(&f.buf[0]))
+//	for i := 0; i < len(payload); i++ {
+//		*(*byte)(unsafe.Pointer(uintptr(unsafe.Pointer(base)) + uintptr(i))) = payload[i]
+//	}
+//
+// 也就是取 &arr[k]，经 uintptr（或 unsafe.Add）加上循环下标再解引用。
+// 当循环上界可能超过数组的常量长度时报告，并给出两种修复建议：
+// 直接用下标访问数组（让边界检查生效），或者把整个循环换成 copy。
+package unsafebounds
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const doc = `report unsafe pointer arithmetic that can run past the end of an array
+
+The analyzer looks for loops of the form
+
+	for i := lo; i < n; i++ {
+		*(*T)(unsafe.Pointer(uintptr(unsafe.Pointer(&arr[k])) + uintptr(i))) = ...
+	}
+
+(also unsafe.Add, a scaled index such as uintptr(i)*unsafe.Sizeof(arr[0]),
+and a base pointer held in a local variable) and reports when n can exceed
+the constant length of arr: either n is a constant that is too large, or n
+is not bounded by len(arr) at all.`
+
+// Analyzer 报告可能越过数组末尾的 unsafe 指针运算。
+var Analyzer = &analysis.Analyzer{
+	Name:     "unsafebounds",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// 修复建议的说明文字。同一种修复在所有诊断里使用相同的文字，便于编辑器批量应用。
+const (
+	fixIndex = "Index the array directly so the bounds check applies"
+	fixCopy  = "Replace the loop with copy, which stops at the end of the array"
+)
+
+func run(pass *analysis.Pass) (any, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.FuncDecl)(nil), (*ast.FuncLit)(nil)}, func(n ast.Node) {
+		var body *ast.BlockStmt
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			body = n.Body
+		case *ast.FuncLit:
+			body = n.Body
+		}
+		if body == nil {
+			return
+		}
+		c := &checker{pass: pass, defs: localDefs(pass.TypesInfo, body)}
+		inFunc(body, func(n ast.Node) {
+			if fs, ok := n.(*ast.ForStmt); ok {
+				c.checkLoop(fs)
+			}
+		})
+	})
+	return nil, nil
+}
+
+// inFunc 遍历 body，但不进入嵌套的函数字面量（它们会作为独立的函数单独检查）。
+func inFunc(body ast.Node, f func(ast.Node)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if n != nil {
+			f(n)
+		}
+		return true
+	})
+}
+
+// def 是局部变量唯一的一次赋值。
+type def struct {
+	init ast.Expr
+	stmt *ast.AssignStmt // 单变量的 v := init；其他形式为 nil（修复时不删除）
+}
+
+// localDefs 收集函数体内只赋值一次的局部变量及其初始化表达式，
+// 用来追踪 base := (*byte)(unsafe.Pointer(&f.buf[0])) 这种先存进变量的基址。
+// 被再次赋值、自增自减或取地址的变量不追踪。
+func localDefs(info *types.Info, body *ast.BlockStmt) map[*types.Var]def {
+	defs := make(map[*types.Var]def)
+	dirty := make(map[*types.Var]bool)
+	mark := func(e ast.Expr) {
+		if id, ok := ast.Unparen(e).(*ast.Ident); ok {
+			if v, ok := info.Uses[id].(*types.Var); ok {
+				dirty[v] = true
+			}
+		}
+	}
+	inFunc(body, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				for _, l := range n.Lhs {
+					mark(l)
+				}
+				return
+			}
+			if len(n.Lhs) != len(n.Rhs) {
+				return
+			}
+			for i, l := range n.Lhs {
+				if id, ok := l.(*ast.Ident); ok {
+					if v, ok := info.Defs[id].(*types.Var); ok {
+						d := def{init: n.Rhs[i]}
+						if len(n.Lhs) == 1 {
+							d.stmt = n
+						}
+						defs[v] = d
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(n.Names) != len(n.Values) {
+				return
+			}
+			for i, id := range n.Names {
+				if v, ok := info.Defs[id].(*types.Var); ok {
+					defs[v] = def{init: n.Values[i]}
+				}
+			}
+		case *ast.IncDecStmt:
+			mark(n.X)
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				mark(n.X)
+			}
+		}
+	})
+	for v := range dirty {
+		delete(defs, v)
+	}
+	return defs
+}
+
+type checker struct {
+	pass *analysis.Pass
+	defs map[*types.Var]def
+}
+
+// loop 是一个 for i := lo; i < bound; i++ 形式的计数循环。
+type loop struct {
+	stmt  *ast.ForStmt
+	v     *types.Var
+	name  string
+	lo    ast.Expr
+	bound ast.Expr
+	incl  bool // 条件是 i <= bound
+}
+
+func (c *checker) countingLoop(fs *ast.ForStmt) (*loop, bool) {
+	info := c.pass.TypesInfo
+	init, ok := fs.Init.(*ast.AssignStmt)
+	if !ok || init.Tok != token.DEFINE || len(init.Lhs) != 1 || len(init.Rhs) != 1 {
+		return nil, false
+	}
+	id, ok := init.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	v, ok := info.Defs[id].(*types.Var)
+	if !ok {
+		return nil, false
+	}
+	cond, ok := ast.Unparen(fs.Cond).(*ast.BinaryExpr)
+	if !ok || (cond.Op != token.LSS && cond.Op != token.LEQ) || !c.isVar(cond.X, v) {
+		return nil, false
+	}
+	post, ok := fs.Post.(*ast.IncDecStmt)
+	if !ok || post.Tok != token.INC || !c.isVar(post.X, v) {
+		return nil, false
+	}
+	// 循环体里改动了 i 就没法推断它的范围。
+	modified := false
+	inFunc(fs.Body, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, l := range n.Lhs {
+				modified = modified || c.isVar(l, v)
+			}
+		case *ast.IncDecStmt:
+			modified = modified || c.isVar(n.X, v)
+		case *ast.UnaryExpr:
+			modified = modified || (n.Op == token.AND && c.isVar(n.X, v))
+		}
+	})
+	if modified {
+		return nil, false
+	}
+	return &loop{stmt: fs, v: v, name: id.Name, lo: init.Rhs[0], bound: cond.Y, incl: cond.Op == token.LEQ}, true
+}
+
+func (c *checker) isVar(e ast.Expr, v *types.Var) bool {
+	id, ok := ast.Unparen(e).(*ast.Ident)
+	return ok && c.pass.TypesInfo.Uses[id] == v
+}
+
+// access 是循环体里的一次 *(*T)(base + scale*i + off) 解引用。
+type access struct {
+	star   *ast.StarExpr
+	typ    types.Type // T
+	origin *origin
+	scale  int64 // 每次 i 加一，地址前进的字节数
+	off    int64 // 相对 &arr[0] 的常量字节偏移（含 k*elemSize）
+}
+
+// origin 是指针运算的起点 &arr[k]。
+type origin struct {
+	arr    ast.Expr // arr（数组或指向数组的指针）
+	length int64
+	elem   types.Type
+	index  int64        // k
+	vars   []*types.Var // 追踪基址时经过的局部变量，离解引用最近的在前
+}
+
+func (c *checker) checkLoop(fs *ast.ForStmt) {
+	l, ok := c.countingLoop(fs)
+	if !ok {
+		return
+	}
+	inFunc(fs.Body, func(n ast.Node) {
+		star, ok := n.(*ast.StarExpr)
+		if !ok {
+			return
+		}
+		if a, ok := c.access(star, l.v); ok {
+			c.report(l, a)
+		}
+	})
+}
+
+// access 识别 *(*T)(unsafe.Pointer(uintptr(P) + uintptr(i) ...)) 与 *(*T)(unsafe.Add(P, i))。
+func (c *checker) access(star *ast.StarExpr, i *types.Var) (*access, bool) {
+	info := c.pass.TypesInfo
+	if tv, ok := info.Types[star]; !ok || !tv.IsValue() {
+		return nil, false // *T 类型表达式
+	}
+	conv, ok := ast.Unparen(star.X).(*ast.CallExpr)
+	if !ok || !c.isConversion(conv) {
+		return nil, false
+	}
+	ptr, ok := info.TypeOf(conv.Fun).Underlying().(*types.Pointer)
+	if !ok {
+		return nil, false
+	}
+	a := &access{star: star, typ: ptr.Elem()}
+
+	arg := ast.Unparen(conv.Args[0])
+	var terms []ast.Expr
+	if call, ok := arg.(*ast.CallExpr); ok && c.isConversion(call) && isUnsafePointer(info.TypeOf(call.Fun)) {
+		// unsafe.Pointer(uintptr(P) + ...)
+		terms = addends(call.Args[0])
+	} else if c.isUnsafeFunc(arg, "Add") {
+		// unsafe.Add(P, n)
+		add := arg.(*ast.CallExpr)
+		terms = append([]ast.Expr{add.Args[0]}, addends(add.Args[1])...)
+	} else {
+		return nil, false
+	}
+
+	for _, t := range terms {
+		if tv, ok := info.Types[t]; ok && tv.Value != nil {
+			off, ok := constant.Int64Val(constant.ToInt(tv.Value))
+			if !ok {
+				return nil, false
+			}
+			a.off += off
+			continue
+		}
+		if scale, ok := c.indexTerm(t, i); ok && a.scale == 0 {
+			a.scale = scale
+			continue
+		}
+		if o, ok := c.origin(t, 0); ok && a.origin == nil {
+			a.origin = o
+			continue
+		}
+		return nil, false // 其他非常量项：无法推断
+	}
+	if a.origin == nil || a.scale <= 0 {
+		return nil, false
+	}
+	a.off += a.origin.index * c.pass.TypesSizes.Sizeof(a.origin.elem)
+	return a, true
+}
+
+// addends 把 a + b + c 展开成各项。
+func addends(e ast.Expr) []ast.Expr {
+	e = ast.Unparen(e)
+	if b, ok := e.(*ast.BinaryExpr); ok && b.Op == token.ADD {
+		return append(addends(b.X), addends(b.Y)...)
+	}
+	return []ast.Expr{e}
+}
+
+// indexTerm 识别 i、uintptr(i)、uintptr(i)*C、C*uintptr(i)，返回每步的字节数。
+func (c *checker) indexTerm(e ast.Expr, i *types.Var) (int64, bool) {
+	e = ast.Unparen(e)
+	if c.isVar(e, i) {
+		return 1, true
+	}
+	switch e := e.(type) {
+	case *ast.CallExpr:
+		if c.isConversion(e) && isInteger(c.pass.TypesInfo.TypeOf(e.Fun)) {
+			return c.indexTerm(e.Args[0], i)
+		}
+	case *ast.BinaryExpr:
+		if e.Op != token.MUL {
+			break
+		}
+		x, y := e.X, e.Y
+		if c.constInt(x) != nil {
+			x, y = y, x
+		}
+		if k := c.constInt(y); k != nil {
+			if s, ok := c.indexTerm(x, i); ok {
+				return s * *k, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// origin 剥掉指针类型转换，找到 &arr[k]；局部变量会追踪到它唯一的初始化表达式。
+func (c *checker) origin(e ast.Expr, depth int) (*origin, bool) {
+	info := c.pass.TypesInfo
+	if depth > 8 {
+		return nil, false
+	}
+	switch e := ast.Unparen(e).(type) {
+	case *ast.CallExpr:
+		if c.isConversion(e) {
+			return c.origin(e.Args[0], depth+1)
+		}
+	case *ast.Ident:
+		if v, ok := info.Uses[e].(*types.Var); ok {
+			if d, ok := c.defs[v]; ok {
+				o, ok := c.origin(d.init, depth+1)
+				if ok {
+					o.vars = append([]*types.Var{v}, o.vars...)
+				}
+				return o, ok
+			}
+		}
+	case *ast.UnaryExpr:
+		if e.Op != token.AND {
+			break
+		}
+		ix, ok := ast.Unparen(e.X).(*ast.IndexExpr)
+		if !ok {
+			break
+		}
+		k := c.constInt(ix.Index)
+		if k == nil {
+			break
+		}
+		t := info.TypeOf(ix.X).Underlying()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem().Underlying()
+		}
+		if arr, ok := t.(*types.Array); ok {
+			return &origin{arr: ix.X, length: arr.Len(), elem: arr.Elem(), index: *k}, true
+		}
+	}
+	return nil, false
+}
+
+func (c *checker) report(l *loop, a *access) {
+	sizes := c.pass.TypesSizes
+	elemSize := sizes.Sizeof(a.origin.elem)
+	arrBytes := a.origin.length * elemSize
+	width := sizes.Sizeof(a.typ)
+	arr := types.ExprString(a.origin.arr)
+
+	lo := int64(0)
+	if k := c.constInt(l.lo); k != nil {
+		lo = *k
+	}
+	var msg string
+	if n := c.constInt(l.bound); n != nil {
+		last := *n - 1
+		if l.incl {
+			last = *n
+		}
+		if last < lo {
+			return // 循环不执行
+		}
+		end := a.off + a.scale*last + width // 最后一次访问结束的位置
+		if end <= arrBytes {
+			return
+		}
+		msg = fmt.Sprintf("unsafe pointer arithmetic goes past the end of %s: %s reaches byte %d of a %d-byte array",
+			arr, types.ExprString(l.stmt.Cond), end-1, arrBytes)
+	} else {
+		if c.boundedByLen(l, a, elemSize, width) {
+			return
+		}
+		msg = fmt.Sprintf("unsafe pointer arithmetic may go past the end of %s: loop bound %s is not limited to its length %d",
+			arr, types.ExprString(l.bound), a.origin.length)
+	}
+
+	d := analysis.Diagnostic{Pos: a.star.Pos(), End: a.star.End(), Message: msg}
+	direct := a.scale == elemSize && width == elemSize && a.off%elemSize == 0 && types.Identical(a.typ, a.origin.elem)
+	if direct {
+		idx := l.name
+		if k := a.off / elemSize; k != 0 {
+			idx = fmt.Sprintf("%d+%s", k, l.name)
+		}
+		d.SuggestedFixes = append(d.SuggestedFixes, analysis.SuggestedFix{
+			Message: fixIndex,
+			TextEdits: append([]analysis.TextEdit{{
+				Pos: a.star.Pos(), End: a.star.End(),
+				NewText: []byte(fmt.Sprintf("%s[%s]", arr, idx)),
+			}}, c.dropDefs(a.origin.vars, a.star)...),
+		})
+		if text, ok := c.copyFix(l, a, arr, elemSize); ok {
+			d.SuggestedFixes = append(d.SuggestedFixes, analysis.SuggestedFix{
+				Message: fixCopy,
+				TextEdits: append([]analysis.TextEdit{{
+					Pos: l.stmt.Pos(), End: l.stmt.End(), NewText: []byte(text),
+				}}, c.dropDefs(a.origin.vars, l.stmt)...),
+			})
+		}
+	}
+	c.pass.Report(d)
+}
+
+// dropDefs 删除只为这次指针运算存在的基址变量（base := ...）：
+// 修复替换掉 replaced 之后，它们不再被使用，留着会编译失败。
+func (c *checker) dropDefs(vars []*types.Var, replaced ast.Node) []analysis.TextEdit {
+	gone := []ast.Node{replaced}
+	var edits []analysis.TextEdit
+	for _, v := range vars {
+		d := c.defs[v]
+		if d.stmt == nil {
+			break
+		}
+		for id, obj := range c.pass.TypesInfo.Uses {
+			if obj == v && !within(id, gone) {
+				return edits
+			}
+		}
+		// 删除整行（连同缩进和换行），而不只是语句本身。
+		pos, end := d.stmt.Pos(), d.stmt.End()
+		if f := c.pass.Fset.File(pos); f != nil {
+			line := f.Line(pos)
+			if f.Line(end) == line && line < f.LineCount() {
+				pos, end = f.LineStart(line), f.LineStart(line+1)
+			}
+		}
+		edits = append(edits, analysis.TextEdit{Pos: pos, End: end})
+		gone = append(gone, d.stmt)
+	}
+	return edits
+}
+
+func within(n ast.Node, in []ast.Node) bool {
+	for _, r := range in {
+		if r.Pos() <= n.Pos() && n.End() <= r.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// boundedByLen 判断非常量上界是否就是 len(arr)（逐元素访问、从 arr[0] 开始）。
+func (c *checker) boundedByLen(l *loop, a *access, elemSize, width int64) bool {
+	call, ok := ast.Unparen(l.bound).(*ast.CallExpr)
+	if !ok || l.incl || len(call.Args) != 1 {
+		return false
+	}
+	if b, ok := c.pass.TypesInfo.Uses[calleeIdent(call.Fun)].(*types.Builtin); !ok || (b.Name() != "len" && b.Name() != "cap") {
+		return false
+	}
+	return types.ExprString(call.Args[0]) == types.ExprString(a.origin.arr) &&
+		a.off == 0 && a.scale == elemSize && width <= elemSize
+}
+
+// copyFix 在循环体只有一句 *(*T)(...) = src[i] 时，给出等价（但在数组末尾停下）的 copy 调用。
+func (c *checker) copyFix(l *loop, a *access, arr string, elemSize int64) (string, bool) {
+	if len(l.stmt.Body.List) != 1 || l.incl {
+		return "", false
+	}
+	as, ok := l.stmt.Body.List[0].(*ast.AssignStmt)
+	if !ok || as.Tok != token.ASSIGN || len(as.Lhs) != 1 || ast.Unparen(as.Lhs[0]) != ast.Expr(a.star) {
+		return "", false
+	}
+	src, ok := ast.Unparen(as.Rhs[0]).(*ast.IndexExpr)
+	if !ok || !c.isVar(src.Index, l.v) {
+		return "", false
+	}
+	lo := c.constInt(l.lo)
+	if lo == nil {
+		return "", false
+	}
+	srcText := types.ExprString(src.X)
+	switch t := c.pass.TypesInfo.TypeOf(src.X).Underlying().(type) {
+	case *types.Slice:
+		if !types.Identical(t.Elem(), a.origin.elem) {
+			return "", false
+		}
+	case *types.Array:
+		if !types.Identical(t.Elem(), a.origin.elem) {
+			return "", false
+		}
+		srcText += "[:]"
+	default:
+		return "", false
+	}
+
+	dstLo := a.off/elemSize + *lo
+	dst := fmt.Sprintf("%s[%d:]", arr, dstLo)
+	if dstLo == 0 {
+		dst = arr + "[:]"
+	}
+	bound := types.ExprString(l.bound)
+	switch {
+	case *lo == 0 && bound == fmt.Sprintf("len(%s)", types.ExprString(src.X)):
+		// 整个 src：copy(dst, src)
+	case *lo == 0:
+		srcText = fmt.Sprintf("%s[:%s]", types.ExprString(src.X), bound)
+	default:
+		srcText = fmt.Sprintf("%s[%d:%s]", types.ExprString(src.X), *lo, bound)
+	}
+	return fmt.Sprintf("copy(%s, %s)", dst, srcText), true
+}
+
+func (c *checker) constInt(e ast.Expr) *int64 {
+	tv, ok := c.pass.TypesInfo.Types[e]
+	if !ok || tv.Value == nil {
+		return nil
+	}
+	v, ok := constant.Int64Val(constant.ToInt(tv.Value))
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+func (c *checker) isConversion(call *ast.CallExpr) bool {
+	tv, ok := c.pass.TypesInfo.Types[call.Fun]
+	return ok && tv.IsType() && len(call.Args) == 1
+}
+
+// isUnsafeFunc 报告 e 是否是对 unsafe.<name> 的调用。
+func (c *checker) isUnsafeFunc(e ast.Expr, name string) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	b, ok := c.pass.TypesInfo.Uses[calleeIdent(call.Fun)].(*types.Builtin)
+	return ok && b.Name() == name && b.Pkg() != nil && b.Pkg().Path() == "unsafe"
+}
+
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	}
+	return nil
+}
+
+func isUnsafePointer(t types.Type) bool {
+	b, ok := t.(*types.Basic)
+	return ok && b.Kind() == types.UnsafePointer
+}
+
+func isInteger(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsInteger != 0
+}